@@ -0,0 +1,156 @@
+package redshift
+
+import (
+	"fmt"
+	"strings"
+)
+
+// https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html
+// Access privileges are stored as aclitem arrays (relacl, nspacl, proacl, datacl, defaclacl), eg
+// {owner=arwdRxt/owner,"group readers=r/owner",=r/owner}
+// We always read them with array_to_string(acl, '|') as Redshift can't cast aclitem[] to text[]
+
+const (
+	granteeTypeUser   = "user"
+	granteeTypeGroup  = "group"
+	granteeTypeRole   = "role"
+	granteeTypePublic = "public"
+)
+
+var aclPrivileges = map[rune]string{
+	'r': "SELECT",
+	'w': "UPDATE",
+	'a': "INSERT",
+	'd': "DELETE",
+	'D': "DROP",
+	'x': "REFERENCES",
+	'R': "RULE",
+	't': "TRIGGER",
+	'X': "EXECUTE",
+	'U': "USAGE",
+	'C': "CREATE",
+	'T': "TEMPORARY",
+	'A': "ALTER",
+	'S': "SHARE",
+}
+
+type aclItem struct {
+	granteeType string
+	grantee     string
+	privileges  string
+	grantor     string
+}
+
+// Privilege names, eg SELECT, INSERT. Grant options (*) are ignored
+func (a aclItem) privilegeNames() []string {
+	var names []string
+	for _, p := range a.privileges {
+		if name, ok := aclPrivileges[p]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (a aclItem) hasPrivilege(privilege rune) bool {
+	return strings.ContainsRune(a.privileges, privilege)
+}
+
+// The grantee as used in GRANT and REVOKE statements, eg GROUP readers
+func (a aclItem) granteeClause() string {
+	switch a.granteeType {
+	case granteeTypeGroup:
+		return "GROUP " + a.grantee
+	case granteeTypeRole:
+		return "ROLE " + a.grantee
+	case granteeTypePublic:
+		return "PUBLIC"
+	default:
+		return a.grantee
+	}
+}
+
+func (a aclItem) isGrantedTo(granteeType string, grantee string) bool {
+	return a.granteeType == granteeType && a.grantee == grantee
+}
+
+// Parses an acl as returned by array_to_string(acl, '|')
+func parseAcl(acl string) ([]aclItem, error) {
+	var items []aclItem
+
+	if acl == "" {
+		return items, nil
+	}
+
+	for _, entry := range strings.Split(acl, "|") {
+		item, err := parseAclItem(entry)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func parseAclItem(entry string) (aclItem, error) {
+	var item aclItem
+
+	grantee, rest, err := splitAclName(entry, '=')
+	if err != nil {
+		return item, fmt.Errorf("Could not parse acl item %s: %s", entry, err)
+	}
+
+	privileges, grantor, err := splitAclName(rest, '/')
+	if err != nil {
+		return item, fmt.Errorf("Could not parse acl item %s: %s", entry, err)
+	}
+
+	item.privileges = privileges
+	item.grantor = strings.Trim(grantor, `"`)
+
+	switch {
+	case grantee == "":
+		item.granteeType = granteeTypePublic
+	case strings.HasPrefix(grantee, "group "):
+		item.granteeType = granteeTypeGroup
+		item.grantee = strings.TrimPrefix(grantee, "group ")
+	case strings.HasPrefix(grantee, "role "):
+		item.granteeType = granteeTypeRole
+		item.grantee = strings.TrimPrefix(grantee, "role ")
+	default:
+		item.granteeType = granteeTypeUser
+		item.grantee = grantee
+	}
+
+	return item, nil
+}
+
+// Splits s at the first separator that isn't inside double quotes, unquoting the first part
+func splitAclName(s string, separator rune) (string, string, error) {
+	var (
+		name     strings.Builder
+		inQuotes bool
+		runes    = []rune(strings.TrimSpace(s))
+	)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '"' && inQuotes && i+1 < len(runes) && runes[i+1] == '"':
+			name.WriteRune('"')
+			i++
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == separator && !inQuotes:
+			return name.String(), string(runes[i+1:]), nil
+		default:
+			name.WriteRune(r)
+		}
+	}
+
+	if separator == '/' {
+		//The grantor is optional
+		return name.String(), "", nil
+	}
+	return "", "", fmt.Errorf("missing %c", separator)
+}
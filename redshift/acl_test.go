@@ -0,0 +1,39 @@
+package redshift
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcl(t *testing.T) {
+	items, err := parseAcl(`owner=arwdRxt/owner|group readers=r/owner|"group etl team"=ar*/owner|=U/owner|"we""ird"=C/"own er"`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []aclItem{
+		{granteeType: granteeTypeUser, grantee: "owner", privileges: "arwdRxt", grantor: "owner"},
+		{granteeType: granteeTypeGroup, grantee: "readers", privileges: "r", grantor: "owner"},
+		{granteeType: granteeTypeGroup, grantee: "etl team", privileges: "ar*", grantor: "owner"},
+		{granteeType: granteeTypePublic, grantee: "", privileges: "U", grantor: "owner"},
+		{granteeType: granteeTypeUser, grantee: `we"ird`, privileges: "C", grantor: "own er"},
+	}
+
+	if !reflect.DeepEqual(items, expected) {
+		t.Fatalf("expected %v, got %v", expected, items)
+	}
+
+	if names := items[2].privilegeNames(); !reflect.DeepEqual(names, []string{"INSERT", "SELECT"}) {
+		t.Fatalf("unexpected privileges %v", names)
+	}
+
+	if clause := items[1].granteeClause(); clause != "GROUP readers" {
+		t.Fatalf("unexpected grantee clause %s", clause)
+	}
+}
+
+func TestParseAclInvalid(t *testing.T) {
+	if _, err := parseAcl("missingseparator"); err == nil {
+		t.Fatal("expected an error for an acl item without =")
+	}
+}
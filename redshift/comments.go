@@ -0,0 +1,49 @@
+package redshift
+
+import (
+	"database/sql"
+	"log"
+)
+
+// https://docs.aws.amazon.com/redshift/latest/dg/r_COMMENT.html
+// Redshift supports comments on databases, schemas, tables, views, columns and constraints.
+// They are stored in pg_description, keyed by the oid of the object and the catalog table it is in.
+
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// objectType is eg SCHEMA, name has to be quoted already. An empty comment removes it
+func setComment(e Execer, objectType string, name string, comment string) error {
+
+	var commentStatement = "COMMENT ON " + objectType + " " + name + " IS "
+	if comment == "" {
+		commentStatement += "NULL"
+	} else {
+		commentStatement += quoteLiteral(comment)
+	}
+
+	log.Print("Comment statement: " + commentStatement)
+
+	if _, err := e.Exec(commentStatement); err != nil {
+		return err
+	}
+	return nil
+}
+
+// catalog is the table the object is in, eg pg_namespace. Returns an empty string if there is no comment
+func readComment(q Queryer, oid string, catalog string) (string, error) {
+
+	var comment string
+
+	err := q.QueryRow(`SELECT pd.description
+		FROM pg_description pd
+		JOIN pg_class c ON c.oid = pd.classoid
+		WHERE pd.objoid = $1
+		AND   pd.objsubid = 0
+		AND   c.relname = $2`, oid, catalog).Scan(&comment)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return comment, err
+}
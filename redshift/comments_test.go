@@ -0,0 +1,52 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestSetComment(t *testing.T) {
+	cases := []struct {
+		comment  string
+		expected string
+	}{
+		{"Loaded nightly", `COMMENT ON SCHEMA "etl" IS 'Loaded nightly'`},
+		{"Owner's schema", `COMMENT ON SCHEMA "etl" IS 'Owner''s schema'`},
+		{"", `COMMENT ON SCHEMA "etl" IS NULL`},
+	}
+
+	for _, c := range cases {
+		var db, statements = newRecordingFakeDb()
+
+		if err := setComment(db, "SCHEMA", quoteIdentifier("etl"), c.comment); err != nil {
+			t.Errorf("%q: err: %s", c.comment, err)
+		}
+		if expected := []string{c.expected}; !reflect.DeepEqual(*statements, expected) {
+			t.Errorf("%q: expected %v, got %v", c.comment, expected, *statements)
+		}
+		db.Close()
+	}
+}
+
+func TestReadComment(t *testing.T) {
+	cases := []struct {
+		rows     [][]driver.Value
+		expected string
+	}{
+		{[][]driver.Value{{"Loaded nightly"}}, "Loaded nightly"},
+		{nil, ""},
+	}
+
+	for _, c := range cases {
+		var db = newFakeDb(fakeResult{match: "FROM pg_description", rows: c.rows})
+
+		comment, err := readComment(db, "100", "pg_namespace")
+		if err != nil {
+			t.Errorf("err: %s", err)
+		} else if comment != c.expected {
+			t.Errorf("expected %q, got %q", c.expected, comment)
+		}
+		db.Close()
+	}
+}
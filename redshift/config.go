@@ -3,6 +3,8 @@ package redshift
 import (
 	"database/sql"
 	"fmt"
+	"sync"
+
 	_ "github.com/lib/pq"
 )
 
@@ -19,18 +21,38 @@ type Config struct {
 type Client struct {
 	config Config
 	db     *sql.DB
+
+	// Connections to other databases on the cluster, by name
+	databases     map[string]*sql.DB
+	databasesLock sync.Mutex
 }
 
 // New redshift client
 func (c *Config) Client() (*Client, error) {
 
+	db, err := c.connect(c.database)
+	if err != nil {
+		return nil, err
+	}
+
+	client := Client{
+		config:    *c,
+		db:        db,
+		databases: map[string]*sql.DB{c.database: db},
+	}
+
+	return &client, nil
+}
+
+func (c *Config) connect(database string) (*sql.DB, error) {
+
 	conninfo := fmt.Sprintf("sslmode=%v user=%v password=%v host=%v port=%v dbname=%v",
 		c.sslmode,
 		c.user,
 		c.password,
 		c.url,
 		c.port,
-		c.database)
+		database)
 
 	db, err := sql.Open("postgres", conninfo)
 	if err != nil {
@@ -38,12 +60,71 @@ func (c *Config) Client() (*Client, error) {
 		return nil, err
 	}
 
-	client := Client{
-		config: *c,
-		db:     db,
+	return db, nil
+}
+
+// Connection to a database on the cluster with the provider credentials. Connections are opened on first use
+// and cached until Disconnect is called. An empty name means the database configured in the provider
+func (c *Client) Connect(database string) (*sql.DB, error) {
+
+	if database == "" {
+		return c.db, nil
 	}
 
-	return &client, nil
+	c.databasesLock.Lock()
+	defer c.databasesLock.Unlock()
+
+	if db, ok := c.databases[database]; ok {
+		return db, nil
+	}
+
+	db, err := c.config.connect(database)
+	if err != nil {
+		return nil, err
+	}
+
+	c.databases[database] = db
+	return db, nil
+}
+
+// Closes the cached connection to a database, eg before it is dropped or renamed, as Redshift refuses to
+// while other sessions are connected to it. The connection to the provider database is kept
+func (c *Client) Disconnect(database string) error {
+
+	if database == "" || database == c.config.database {
+		return nil
+	}
+
+	c.databasesLock.Lock()
+	defer c.databasesLock.Unlock()
+
+	db, ok := c.databases[database]
+	if !ok {
+		return nil
+	}
+
+	delete(c.databases, database)
+	return db.Close()
+}
+
+// All databases on the cluster that can be connected to, excluding templates and the internal padb_harvest
+func (c *Client) listDatabases() ([]string, error) {
+
+	rows, err := c.db.Query("SELECT datname FROM pg_database_info WHERE datallowconn AND NOT datistemplate AND datname <> 'padb_harvest' ORDER BY datname")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, err
+		}
+		databases = append(databases, database)
+	}
+	return databases, rows.Err()
 }
 
 //When do we close the connection?
@@ -0,0 +1,54 @@
+package redshift
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestListDatabases(t *testing.T) {
+	var db = newFakeDb(fakeResult{match: "FROM pg_database_info", rows: [][]driver.Value{{"analytics"}, {"dev"}}})
+	defer db.Close()
+
+	var client = &Client{db: db}
+
+	databases, err := client.listDatabases()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := []string{"analytics", "dev"}; !reflect.DeepEqual(databases, expected) {
+		t.Errorf("expected %v, got %v", expected, databases)
+	}
+}
+
+func TestClientConnectReusesConnections(t *testing.T) {
+	var (
+		db        = newFakeDb()
+		analytics = newFakeDb()
+	)
+	defer db.Close()
+	defer analytics.Close()
+
+	var client = &Client{
+		config:    Config{database: "dev"},
+		db:        db,
+		databases: map[string]*sql.DB{"dev": db, "analytics": analytics},
+	}
+
+	cases := map[string]*sql.DB{
+		"":          db,
+		"dev":       db,
+		"analytics": analytics,
+	}
+
+	for database, expected := range cases {
+		connection, err := client.Connect(database)
+		if err != nil {
+			t.Fatalf("%s: err: %s", database, err)
+		}
+		if connection != expected {
+			t.Errorf("%s: expected the cached connection", database)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package redshift
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRedshiftDatabase() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRedshiftDatabaseRead,
+
+		Schema: map[string]*schema.Schema{
+			//Either database_name or database_id has to be set
+			"database_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"database_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"owner": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"connection_limit": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftDatabaseRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient := meta.(*Client).db
+
+	datid, err := dataSourceLookupId(redshiftClient, d, "database_id", "database_name", "database",
+		"SELECT datid FROM pg_database_info WHERE datname = $1")
+	if err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(datid))
+	d.Set("database_id", datid)
+
+	if err := readRedshiftDatabase(d, meta.(*Client)); err != nil {
+		return dataSourceNotFound(err, "database", d.Id())
+	}
+
+	return nil
+}
@@ -0,0 +1,400 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Answers "what can this user, group or role do?" by decoding the acls of databases, schemas, tables, views,
+// functions and procedures. A privilege can come from a grant to the principal itself (direct), to a group the user
+// is in (group:name), to a role the principal has been granted (role:name), to PUBLIC (public), or from owning
+// the object (owner). Superusers can do everything regardless of acls, which is reported by the superuser attribute.
+// An object whose acl is null has the default privileges: everything for the owner, EXECUTE on functions and
+// TEMPORARY on databases for PUBLIC.
+
+func dataSourceRedshiftEffectivePrivileges() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRedshiftEffectivePrivilegesRead,
+
+		Schema: map[string]*schema.Schema{
+			//Exactly one of user_id, group_id and role_id has to be set
+			"user_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"group_id", "role_id"},
+			},
+			"group_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"user_id", "role_id"},
+			},
+			"role_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"user_id", "group_id"},
+			},
+			"databases": { //Defaults to every database on the cluster
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"superuser": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"database_privileges": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     effectivePrivilegeSchema(),
+			},
+			"schema_privileges": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     effectivePrivilegeSchema("schema"),
+			},
+			"table_privileges": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     effectivePrivilegeSchema("schema", "table"),
+			},
+			"function_privileges": { //Functions and procedures, the name includes the argument types
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     effectivePrivilegeSchema("schema", "function"),
+			},
+		},
+	}
+}
+
+func effectivePrivilegeSchema(nameAttributes ...string) *schema.Resource {
+	var privilegeSchema = map[string]*schema.Schema{
+		"database": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"privileges": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"source": { //direct, owner, public, group:name or role:name
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+	for _, attribute := range nameAttributes {
+		privilegeSchema[attribute] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	return &schema.Resource{Schema: privilegeSchema}
+}
+
+// A grantee whose privileges apply to the principal
+type effectiveGrantee struct {
+	granteeType string
+	name        string
+	source      string
+}
+
+// Each query returns the database or schema, the object name, the owner and the acl of the object
+var effectivePrivilegeQueries = map[string]string{
+	"schema_privileges": `SELECT nsp.nspname, nsp.nspname, COALESCE(pu.usename, ''), array_to_string(nsp.nspacl, '|')
+		FROM pg_namespace nsp
+		LEFT JOIN pg_user pu ON pu.usesysid = nsp.nspowner
+		WHERE nsp.nspname NOT ILIKE 'pg\_temp\_%'`,
+	"table_privileges": `SELECT nsp.nspname, c.relname, COALESCE(pu.usename, ''), array_to_string(c.relacl, '|')
+		FROM pg_class c
+		JOIN pg_namespace nsp ON nsp.oid = c.relnamespace
+		LEFT JOIN pg_user pu ON pu.usesysid = c.relowner
+		WHERE c.relkind IN ('r','v')
+		AND   nsp.nspname NOT ILIKE 'pg\_temp\_%'`,
+	"function_privileges": `SELECT nsp.nspname, p.proname || '(' || oidvectortypes(p.proargtypes) || ')', COALESCE(pu.usename, ''), array_to_string(p.proacl, '|')
+		FROM pg_proc_info p
+		JOIN pg_namespace nsp ON nsp.oid = p.pronamespace
+		LEFT JOIN pg_user pu ON pu.usesysid = p.proowner`,
+}
+
+// The object type of each attribute, see defaultAcl
+var effectivePrivilegeObjectTypes = map[string]string{
+	"schema_privileges":   "schema",
+	"table_privileges":    "table",
+	"function_privileges": "function",
+}
+
+var effectivePrivilegeNameAttributes = map[string][]string{
+	"schema_privileges":   {"schema"},
+	"table_privileges":    {"schema", "table"},
+	"function_privileges": {"schema", "function"},
+}
+
+var databasePrivilegesQuery = `SELECT pgd.datname, pgd.datname, COALESCE(pu.usename, ''), array_to_string(pgd.datacl, '|')
+	FROM pg_database pgd
+	LEFT JOIN pg_user pu ON pu.usesysid = pgd.datdba
+	WHERE pgd.datname <> 'padb_harvest'
+	AND   NOT pgd.datistemplate`
+
+func dataSourceRedshiftEffectivePrivilegesRead(d *schema.ResourceData, meta interface{}) error {
+
+	client := meta.(*Client)
+
+	grantees, superuser, id, err := effectiveGrantees(client.db, d)
+	if err != nil {
+		return err
+	}
+
+	var databases []string
+	if v, ok := d.GetOk("databases"); ok {
+		for _, database := range v.([]interface{}) {
+			databases = append(databases, database.(string))
+		}
+	} else if databases, err = client.listDatabases(); err != nil {
+		return fmt.Errorf("Could not list databases: %s", err)
+	}
+
+	//Database privileges are cluster wide, so they only have to be read once
+	databasePrivileges, err := readEffectivePrivileges(client.db, databasePrivilegesQuery, "database", "", nil, grantees)
+	if err != nil {
+		return err
+	}
+
+	var privileges = map[string][]map[string]interface{}{}
+	for _, database := range databases {
+		db, err := client.Connect(database)
+		if err != nil {
+			return fmt.Errorf("Could not connect to database %s: %s", database, err)
+		}
+
+		for attribute, query := range effectivePrivilegeQueries {
+			databaseObjectPrivileges, err := readEffectivePrivileges(db, query, effectivePrivilegeObjectTypes[attribute], database, effectivePrivilegeNameAttributes[attribute], grantees)
+			if err != nil {
+				return fmt.Errorf("Could not read %s in database %s: %s", attribute, database, err)
+			}
+			privileges[attribute] = append(privileges[attribute], databaseObjectPrivileges...)
+		}
+	}
+
+	d.SetId(id)
+	d.Set("superuser", superuser)
+	d.Set("database_privileges", databasePrivileges)
+	for attribute := range effectivePrivilegeQueries {
+		if privileges[attribute] == nil {
+			privileges[attribute] = []map[string]interface{}{}
+		}
+		d.Set(attribute, privileges[attribute])
+	}
+
+	return nil
+}
+
+// The grantees whose privileges the principal has, whether the principal is a superuser, and the id of the data source
+func effectiveGrantees(q Queryer, d *schema.ResourceData) ([]effectiveGrantee, bool, string, error) {
+
+	var (
+		grantees  []effectiveGrantee
+		superuser bool
+		roleIds   []int
+		id        string
+	)
+
+	if v, ok := d.GetOk("user_id"); ok {
+		var username string
+		err := q.QueryRow("SELECT usename, usesuper FROM pg_user_info WHERE usesysid = $1", v.(int)).Scan(&username, &superuser)
+		if err != nil {
+			return nil, false, "", dataSourceNotFound(err, "user", strconv.Itoa(v.(int)))
+		}
+		grantees = append(grantees, effectiveGrantee{granteeTypeUser, username, "direct"}, effectiveGrantee{granteeTypeUser, username, "owner"})
+
+		groupIds, err := GetGroupIdsForUsesysid(q, v.(int))
+		if err != nil {
+			return nil, false, "", err
+		}
+		for _, groupId := range groupIds {
+			groupName, err := GetGroupNameForGroupId(q, groupId)
+			if err != nil {
+				return nil, false, "", err
+			}
+			grantees = append(grantees, effectiveGrantee{granteeTypeGroup, groupName, "group:" + groupName})
+		}
+
+		if roleIds, err = queryInts(q, "SELECT role_id FROM svv_user_grants WHERE user_id = $1", v.(int)); err != nil {
+			return nil, false, "", err
+		}
+		id = "user_" + strconv.Itoa(v.(int))
+	} else if v, ok := d.GetOk("group_id"); ok {
+		groupName, err := GetGroupNameForGroupId(q, v.(int))
+		if err != nil {
+			return nil, false, "", dataSourceNotFound(err, "group", strconv.Itoa(v.(int)))
+		}
+		grantees = append(grantees, effectiveGrantee{granteeTypeGroup, groupName, "direct"})
+		id = "group_" + strconv.Itoa(v.(int))
+	} else if v, ok := d.GetOk("role_id"); ok {
+		roleIds = []int{v.(int)}
+		id = "role_" + strconv.Itoa(v.(int))
+	} else {
+		return nil, false, "", NewError("One of user_id, group_id or role_id has to be set")
+	}
+
+	roleGrantees, err := effectiveRoleGrantees(q, roleIds, d.Get("role_id").(int))
+	if err != nil {
+		return nil, false, "", err
+	}
+	grantees = append(grantees, roleGrantees...)
+
+	grantees = append(grantees, effectiveGrantee{granteeTypePublic, "", "public"})
+
+	return grantees, superuser, id, nil
+}
+
+// Roles can be granted to roles, so this follows the grants until no new roles are found
+func effectiveRoleGrantees(q Queryer, roleIds []int, directRoleId int) ([]effectiveGrantee, error) {
+
+	var (
+		grantees []effectiveGrantee
+		seen     = map[int]bool{}
+	)
+
+	for len(roleIds) > 0 {
+		var roleId = roleIds[0]
+		roleIds = roleIds[1:]
+
+		if seen[roleId] {
+			continue
+		}
+		seen[roleId] = true
+
+		var roleName string
+		if err := q.QueryRow("SELECT role_name FROM svv_roles WHERE role_id = $1", roleId).Scan(&roleName); err != nil {
+			return nil, dataSourceNotFound(err, "role", strconv.Itoa(roleId))
+		}
+
+		var source = "role:" + roleName
+		if roleId == directRoleId {
+			source = "direct"
+		}
+		grantees = append(grantees, effectiveGrantee{granteeTypeRole, roleName, source})
+
+		grantedRoleIds, err := queryInts(q, "SELECT granted_role_id FROM svv_role_grants WHERE role_id = $1", roleId)
+		if err != nil {
+			return nil, err
+		}
+		roleIds = append(roleIds, grantedRoleIds...)
+	}
+
+	return grantees, nil
+}
+
+// What the owner and PUBLIC can do on an object whose acl is null, by object type
+var (
+	defaultOwnerPrivileges = map[string]string{
+		"database": "CT",
+		"schema":   "UC",
+		"table":    "arwdRxt",
+		"function": "X",
+	}
+	defaultPublicPrivileges = map[string]string{
+		"database": "T",
+		"function": "X",
+	}
+)
+
+// The acl that applies to an object, which is the default one if the acl is null
+func effectiveAcl(acl sql.NullString, objectType string, owner string) ([]aclItem, error) {
+
+	if acl.Valid {
+		return parseAcl(acl.String)
+	}
+
+	var items []aclItem
+	if owner != "" {
+		items = append(items, aclItem{granteeType: granteeTypeUser, grantee: owner, privileges: defaultOwnerPrivileges[objectType]})
+	}
+	if privileges, ok := defaultPublicPrivileges[objectType]; ok {
+		items = append(items, aclItem{granteeType: granteeTypePublic, privileges: privileges})
+	}
+	return items, nil
+}
+
+// The privileges each grantee gives the principal on an object, in the order of the grantees. The privileges of the
+// owner are reported with the owner source rather than direct, as long as the owner hasn't revoked them from themselves
+func granteePrivileges(aclItems []aclItem, owner string, grantees []effectiveGrantee) []map[string]interface{} {
+
+	var privileges []map[string]interface{}
+	for _, grantee := range grantees {
+		var isOwner = grantee.granteeType == granteeTypeUser && grantee.name == owner
+		switch {
+		case grantee.source == "owner" && !isOwner:
+			continue
+		case grantee.source == "direct" && isOwner:
+			continue
+		}
+
+		var privilegeNames []string
+		for _, item := range aclItems {
+			if item.isGrantedTo(grantee.granteeType, grantee.name) {
+				privilegeNames = append(privilegeNames, item.privilegeNames()...)
+			}
+		}
+
+		if len(privilegeNames) == 0 {
+			continue
+		}
+
+		privileges = append(privileges, map[string]interface{}{
+			"privileges": privilegeNames,
+			"source":     grantee.source,
+		})
+	}
+	return privileges
+}
+
+// Returns one entry per object and grantee that gives the principal privileges on it
+func readEffectivePrivileges(q Queryer, query string, objectType string, database string, nameAttributes []string, grantees []effectiveGrantee) ([]map[string]interface{}, error) {
+
+	rows, err := q.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var privileges = []map[string]interface{}{}
+	for rows.Next() {
+		var (
+			container string
+			name      string
+			owner     string
+			acl       sql.NullString
+		)
+		if err := rows.Scan(&container, &name, &owner, &acl); err != nil {
+			return nil, err
+		}
+
+		aclItems, err := effectiveAcl(acl, objectType, owner)
+		if err != nil {
+			return nil, err
+		}
+
+		var objectDatabase = database
+		if objectDatabase == "" {
+			//Database privileges, the object is the database
+			objectDatabase = name
+		}
+
+		for _, privilege := range granteePrivileges(aclItems, owner, grantees) {
+			privilege["database"] = objectDatabase
+			switch len(nameAttributes) {
+			case 1:
+				privilege[nameAttributes[0]] = name
+			case 2:
+				privilege[nameAttributes[0]] = container
+				privilege[nameAttributes[1]] = name
+			}
+			privileges = append(privileges, privilege)
+		}
+	}
+	return privileges, rows.Err()
+}
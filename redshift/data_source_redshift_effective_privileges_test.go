@@ -0,0 +1,188 @@
+package redshift
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestEffectiveAcl(t *testing.T) {
+	cases := []struct {
+		name       string
+		acl        sql.NullString
+		objectType string
+		owner      string
+		expected   []aclItem
+	}{
+		{
+			name:       "null table acl",
+			objectType: "table",
+			owner:      "etl",
+			expected:   []aclItem{{granteeType: granteeTypeUser, grantee: "etl", privileges: "arwdRxt"}},
+		},
+		{
+			name:       "null function acl",
+			objectType: "function",
+			owner:      "etl",
+			expected: []aclItem{
+				{granteeType: granteeTypeUser, grantee: "etl", privileges: "X"},
+				{granteeType: granteeTypePublic, privileges: "X"},
+			},
+		},
+		{
+			name:       "null database acl",
+			objectType: "database",
+			owner:      "etl",
+			expected: []aclItem{
+				{granteeType: granteeTypeUser, grantee: "etl", privileges: "CT"},
+				{granteeType: granteeTypePublic, privileges: "T"},
+			},
+		},
+		{
+			name:       "null schema acl without owner",
+			objectType: "schema",
+			expected:   nil,
+		},
+		{
+			name:       "granted",
+			acl:        sql.NullString{String: "etl=UC/etl|group readers=U/etl", Valid: true},
+			objectType: "schema",
+			owner:      "etl",
+			expected: []aclItem{
+				{granteeType: granteeTypeUser, grantee: "etl", privileges: "UC", grantor: "etl"},
+				{granteeType: granteeTypeGroup, grantee: "readers", privileges: "U", grantor: "etl"},
+			},
+		},
+		{
+			name:       "everything revoked",
+			acl:        sql.NullString{String: "", Valid: true},
+			objectType: "function",
+			owner:      "etl",
+			expected:   nil,
+		},
+	}
+
+	for _, c := range cases {
+		items, err := effectiveAcl(c.acl, c.objectType, c.owner)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if !reflect.DeepEqual(items, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, items)
+		}
+	}
+}
+
+func TestGranteePrivileges(t *testing.T) {
+	var grantees = []effectiveGrantee{
+		{granteeTypeUser, "etl", "direct"},
+		{granteeTypeUser, "etl", "owner"},
+		{granteeTypeGroup, "loaders", "group:loaders"},
+		{granteeTypePublic, "", "public"},
+	}
+
+	cases := []struct {
+		name     string
+		acl      sql.NullString
+		owner    string
+		expected []map[string]interface{}
+	}{
+		{
+			name:  "owner with null acl",
+			owner: "etl",
+			expected: []map[string]interface{}{
+				{"privileges": []string{"INSERT", "SELECT", "UPDATE", "DELETE", "RULE", "REFERENCES", "TRIGGER"}, "source": "owner"},
+			},
+		},
+		{
+			name:     "someone else's table with null acl",
+			owner:    "admin",
+			expected: nil,
+		},
+		{
+			name:  "granted directly, to a group and to public",
+			acl:   sql.NullString{String: "admin=arwdRxt/admin|etl=a/admin|group loaders=w/admin|=r/admin", Valid: true},
+			owner: "admin",
+			expected: []map[string]interface{}{
+				{"privileges": []string{"INSERT"}, "source": "direct"},
+				{"privileges": []string{"UPDATE"}, "source": "group:loaders"},
+				{"privileges": []string{"SELECT"}, "source": "public"},
+			},
+		},
+		{
+			name:  "owner with an acl",
+			acl:   sql.NullString{String: "etl=ar/etl|group loaders=r/etl", Valid: true},
+			owner: "etl",
+			expected: []map[string]interface{}{
+				{"privileges": []string{"INSERT", "SELECT"}, "source": "owner"},
+				{"privileges": []string{"SELECT"}, "source": "group:loaders"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		items, err := effectiveAcl(c.acl, "table", c.owner)
+		if err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if privileges := granteePrivileges(items, c.owner, grantees); !reflect.DeepEqual(privileges, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, privileges)
+		}
+	}
+}
+
+func TestEffectiveGranteesOfGroup(t *testing.T) {
+	var db = newFakeDb(fakeResult{match: "FROM pg_group", rows: [][]driver.Value{{"readers"}}})
+	defer db.Close()
+
+	var d = schema.TestResourceDataRaw(t, dataSourceRedshiftEffectivePrivileges().Schema, map[string]interface{}{"group_id": 200})
+
+	grantees, superuser, id, err := effectiveGrantees(db, d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected = []effectiveGrantee{
+		{granteeTypeGroup, "readers", "direct"},
+		{granteeTypePublic, "", "public"},
+	}
+	if !reflect.DeepEqual(grantees, expected) || superuser || id != "group_200" {
+		t.Errorf("expected %v, false, group_200, got %v, %t, %s", expected, grantees, superuser, id)
+	}
+}
+
+func TestReadEffectivePrivileges(t *testing.T) {
+	var db = newFakeDb(fakeResult{match: "FROM pg_class", rows: [][]driver.Value{
+		{"etl", "orders", "etl", "etl=arwdRxt/etl|group readers=r/etl"},
+		{"etl", "customers", "admin", "=r/admin|loader=a/admin"},
+		{"etl", "events", "etl", nil},
+	}})
+	defer db.Close()
+
+	var grantees = []effectiveGrantee{
+		{granteeTypeUser, "etl", "direct"},
+		{granteeTypeUser, "etl", "owner"},
+		{granteeTypeGroup, "readers", "group:readers"},
+		{granteeTypePublic, "", "public"},
+	}
+
+	privileges, err := readEffectivePrivileges(db, effectivePrivilegeQueries["table_privileges"], "table", "dev", []string{"schema", "table"}, grantees)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var allTablePrivileges = []string{"INSERT", "SELECT", "UPDATE", "DELETE", "RULE", "REFERENCES", "TRIGGER"}
+	var expected = []map[string]interface{}{
+		{"database": "dev", "schema": "etl", "table": "orders", "source": "owner", "privileges": allTablePrivileges},
+		{"database": "dev", "schema": "etl", "table": "orders", "source": "group:readers", "privileges": []string{"SELECT"}},
+		{"database": "dev", "schema": "etl", "table": "customers", "source": "public", "privileges": []string{"SELECT"}},
+		//A null acl gives the owner every privilege
+		{"database": "dev", "schema": "etl", "table": "events", "source": "owner", "privileges": allTablePrivileges},
+	}
+	if !reflect.DeepEqual(privileges, expected) {
+		t.Errorf("expected %v, got %v", expected, privileges)
+	}
+}
@@ -0,0 +1,61 @@
+package redshift
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRedshiftGroup() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRedshiftGroupRead,
+
+		Schema: map[string]*schema.Schema{
+			//Either group_name or group_id has to be set
+			"group_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"group_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			//usesysids of the members
+			"users": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftGroupRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient := meta.(*Client).db
+
+	grosysid, err := dataSourceLookupId(redshiftClient, d, "group_id", "group_name", "group",
+		"SELECT grosysid FROM pg_group WHERE groname = $1")
+	if err != nil {
+		return err
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	d.SetId(strconv.Itoa(grosysid))
+	d.Set("group_id", grosysid)
+
+	if err := readRedshiftGroup(d, tx); err != nil {
+		tx.Rollback()
+		return dataSourceNotFound(err, "group", d.Id())
+	}
+
+	tx.Commit()
+	return nil
+}
@@ -0,0 +1,87 @@
+package redshift
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// The plural data sources (redshift_users, redshift_groups, redshift_databases and redshift_roles) list the ids and
+// names of all objects whose name matches a LIKE pattern, eg etl_%. ids and names are in the same order
+
+func dataSourceRedshiftUsers() *schema.Resource {
+	return dataSourceRedshiftNameList("SELECT usesysid, usename FROM pg_user_info WHERE usename LIKE $1 ORDER BY usename")
+}
+
+func dataSourceRedshiftGroups() *schema.Resource {
+	return dataSourceRedshiftNameList("SELECT grosysid, groname FROM pg_group WHERE groname LIKE $1 ORDER BY groname")
+}
+
+func dataSourceRedshiftDatabases() *schema.Resource {
+	return dataSourceRedshiftNameList("SELECT datid, datname FROM pg_database_info WHERE datname LIKE $1 ORDER BY datname")
+}
+
+func dataSourceRedshiftRoles() *schema.Resource {
+	return dataSourceRedshiftNameList("SELECT role_id, role_name FROM svv_roles WHERE role_name LIKE $1 ORDER BY role_name")
+}
+
+// query selects the id and name of objects whose name is LIKE $1
+func dataSourceRedshiftNameList(query string) *schema.Resource {
+	return &schema.Resource{
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return dataSourceRedshiftNameListRead(d, meta, query)
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name_pattern": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "%",
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftNameListRead(d *schema.ResourceData, meta interface{}, query string) error {
+
+	redshiftClient := meta.(*Client).db
+
+	rows, err := redshiftClient.Query(query, d.Get("name_pattern").(string))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		ids   = []int{}
+		names = []string{}
+	)
+	for rows.Next() {
+		var (
+			id   int
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.SetId(d.Get("name_pattern").(string))
+	d.Set("ids", ids)
+	d.Set("names", names)
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceRedshiftNameListRead(t *testing.T) {
+	var db = newFakeDb(fakeResult{match: "FROM pg_group", rows: [][]driver.Value{{int64(101), "etl_loaders"}, {int64(100), "etl_readers"}}})
+	defer db.Close()
+
+	var d = schema.TestResourceDataRaw(t, dataSourceRedshiftGroups().Schema, map[string]interface{}{"name_pattern": "etl_%"})
+
+	if err := dataSourceRedshiftGroups().Read(d, &Client{db: db}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != "etl_%" {
+		t.Errorf("expected the pattern as id, got %s", d.Id())
+	}
+	if ids := d.Get("ids").([]interface{}); !reflect.DeepEqual(ids, []interface{}{101, 100}) {
+		t.Errorf("expected ids [101 100], got %v", ids)
+	}
+	if names := d.Get("names").([]interface{}); !reflect.DeepEqual(names, []interface{}{"etl_loaders", "etl_readers"}) {
+		t.Errorf("expected names [etl_loaders etl_readers], got %v", names)
+	}
+}
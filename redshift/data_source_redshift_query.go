@@ -0,0 +1,210 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Runs a single read only SELECT and returns the rows as a list of maps from column name to value.
+// NULL values are left out of the maps. The query runs in a READ ONLY transaction which is always rolled back,
+// so even a query that slips through the SELECT check can't change anything.
+
+func dataSourceRedshiftQuery() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRedshiftQueryRead,
+
+		Schema: map[string]*schema.Schema{
+			"query": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateReadOnlyQuery,
+			},
+			"parameters": { //Bind parameters, referenced as $1, $2... in the query
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rows": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftQueryRead(d *schema.ResourceData, meta interface{}) error {
+
+	var query = d.Get("query").(string)
+
+	if _, errs := validateReadOnlyQuery(query, "query"); len(errs) > 0 {
+		return errs[0]
+	}
+
+	var parameters []interface{}
+	for _, parameter := range d.Get("parameters").([]interface{}) {
+		parameters = append(parameters, parameter)
+	}
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+	//Nothing to commit
+	defer tx.Rollback()
+
+	log.Print("Running query: " + query)
+
+	columns, rows, err := queryRowsAsStrings(tx, query, parameters...)
+	if err != nil {
+		return fmt.Errorf("Could not run query: %s", err)
+	}
+
+	var results = []map[string]interface{}{}
+	for _, row := range rows {
+		var result = map[string]interface{}{}
+		for i, column := range columns {
+			if row[i].Valid {
+				result[column] = row[i].String
+			}
+		}
+		results = append(results, result)
+	}
+
+	d.SetId(strconv.Itoa(hashcode.String(query + fmt.Sprint(parameters))))
+	d.Set("rows", results)
+
+	return nil
+}
+
+// Scans every value as a string, whatever its type
+func queryRowsAsStrings(q Queryer, query string, args ...interface{}) ([]string, [][]sql.NullString, error) {
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results [][]sql.NullString
+	for rows.Next() {
+		var (
+			values    = make([]sql.NullString, len(columns))
+			valuePtrs = make([]interface{}, len(columns))
+		)
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, nil, err
+		}
+		results = append(results, values)
+	}
+	return columns, results, rows.Err()
+}
+
+var readOnlyStatementRegex = regexp.MustCompile(`(?i)^(select|with)\b`)
+
+// Only a single SELECT (optionally with a WITH clause) is allowed. This only catches mistakes early, at plan time,
+// the READ ONLY transaction the query runs in is what actually stops it from changing anything
+func validateReadOnlyQuery(v interface{}, k string) (ws []string, errors []error) {
+
+	query, err := stripSqlLiteralsAndComments(v.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%s is invalid: %s", k, err))
+		return
+	}
+
+	query = strings.TrimSpace(query)
+	query = strings.TrimSpace(strings.TrimSuffix(query, ";"))
+
+	if !readOnlyStatementRegex.MatchString(query) {
+		errors = append(errors, fmt.Errorf("%s must be a SELECT statement", k))
+	}
+	if strings.Contains(query, ";") {
+		errors = append(errors, fmt.Errorf("%s must be a single statement", k))
+	}
+	return
+}
+
+// Replaces comments with a space and empties string literals and quoted identifiers, eg
+// SELECT 'a;b' -- note becomes SELECT followed by an empty literal, so what is left can be checked without looking inside them
+func stripSqlLiteralsAndComments(query string) (string, error) {
+
+	var (
+		stripped strings.Builder
+		runes    = []rune(query)
+	)
+
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\'' || runes[i] == '"':
+			var quote = runes[i]
+			var closed = false
+			for i++; i < len(runes); i++ {
+				if runes[i] == quote {
+					//A doubled quote is an escaped quote
+					if i+1 < len(runes) && runes[i+1] == quote {
+						i++
+						continue
+					}
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				return "", fmt.Errorf("unterminated %c", quote)
+			}
+			stripped.WriteRune(quote)
+			stripped.WriteRune(quote)
+		case runes[i] == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			//The newline itself is kept
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+			stripped.WriteRune(' ')
+		case runes[i] == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			var closed = false
+			for i += 2; i+1 < len(runes); i++ {
+				if runes[i] == '*' && runes[i+1] == '/' {
+					i++
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				return "", fmt.Errorf("unterminated comment")
+			}
+			stripped.WriteRune(' ')
+		default:
+			stripped.WriteRune(runes[i])
+		}
+	}
+	return stripped.String(), nil
+}
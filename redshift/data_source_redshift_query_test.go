@@ -0,0 +1,60 @@
+package redshift
+
+import (
+	"testing"
+)
+
+func TestValidateReadOnlyQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		valid bool
+	}{
+		{"SELECT 1", true},
+		{"select * from users;", true},
+		{"  WITH t AS (SELECT 1) SELECT * FROM t", true},
+		{"-- leading comment\nSELECT 1", true},
+		{"/* block\ncomment */ SELECT 1", true},
+		{"SELECT 'a;b'", true},
+		{"SELECT * FROM notes WHERE note = 'a--b'", true},
+		{"SELECT 'it''s; fine'", true},
+		{`SELECT 1 AS "semi;colon"`, true},
+		{"SELECT 1 -- trailing; comment", true},
+		{"SELECT 1; DROP TABLE users", false},
+		{"SELECT 1; -- comment\n DELETE FROM users", false},
+		{"DELETE FROM users", false},
+		{"/* SELECT */ DELETE FROM users", false},
+		{"-- SELECT\nDELETE FROM users", false},
+		{"selectivity", false},
+		{"SELECT 'unterminated", false},
+		{"SELECT 1 /* unterminated", false},
+	}
+
+	for _, c := range cases {
+		_, errs := validateReadOnlyQuery(c.query, "query")
+		if valid := len(errs) == 0; valid != c.valid {
+			t.Errorf("%q: expected valid=%t, got errors %v", c.query, c.valid, errs)
+		}
+	}
+}
+
+func TestStripSqlLiteralsAndComments(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT 'a--b' FROM t", "SELECT '' FROM t"},
+		{"SELECT 'it''s' -- comment\nFROM t", "SELECT ''  \nFROM t"},
+		{`SELECT "we""ird" /* x */ FROM t`, `SELECT ""   FROM t`},
+		{"SELECT 1", "SELECT 1"},
+	}
+
+	for _, c := range cases {
+		stripped, err := stripSqlLiteralsAndComments(c.query)
+		if err != nil {
+			t.Fatalf("%q: err: %s", c.query, err)
+		}
+		if stripped != c.expected {
+			t.Errorf("%q: expected %q, got %q", c.query, c.expected, stripped)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package redshift
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// https://docs.aws.amazon.com/redshift/latest/dg/r_SVV_ROLES.html
+// https://docs.aws.amazon.com/redshift/latest/dg/r_SVV_USER_GRANTS.html
+// https://docs.aws.amazon.com/redshift/latest/dg/r_SVV_ROLE_GRANTS.html
+
+func dataSourceRedshiftRole() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRedshiftRoleRead,
+
+		Schema: map[string]*schema.Schema{
+			//Either role_name or role_id has to be set
+			"role_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"role_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			//Name of the user that owns the role
+			"owner": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			//Set for roles of identity provider groups
+			"external_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			//usesysids of the users that were granted the role
+			"users": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			//Names of the roles granted to this role
+			"granted_roles": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftRoleRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient := meta.(*Client).db
+
+	roleId, err := dataSourceLookupId(redshiftClient, d, "role_id", "role_name", "role",
+		"SELECT role_id FROM svv_roles WHERE role_name = $1")
+	if err != nil {
+		return err
+	}
+
+	var (
+		roleName   string
+		owner      sql.NullString
+		externalId sql.NullString
+	)
+
+	err = redshiftClient.QueryRow("SELECT role_name, role_owner, external_id FROM svv_roles WHERE role_id = $1", roleId).Scan(&roleName, &owner, &externalId)
+	if err != nil {
+		return dataSourceNotFound(err, "role", strconv.Itoa(roleId))
+	}
+
+	users, err := queryInts(redshiftClient, "SELECT user_id FROM svv_user_grants WHERE role_id = $1", roleId)
+	if err != nil {
+		return err
+	}
+
+	grantedRoles, err := queryStrings(redshiftClient, "SELECT granted_role_name FROM svv_role_grants WHERE role_id = $1", roleId)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(roleId))
+	d.Set("role_id", roleId)
+	d.Set("role_name", roleName)
+	d.Set("owner", owner.String)
+	d.Set("external_id", externalId.String)
+	d.Set("users", users)
+	d.Set("granted_roles", grantedRoles)
+
+	return nil
+}
+
+// Reads the first column of all rows
+func queryInts(q Queryer, query string, args ...interface{}) ([]int, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values = []int{}
+	for rows.Next() {
+		var value int
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
@@ -1,6 +1,7 @@
 package redshift
 
 import (
+	"database/sql"
 	"log"
 	"strconv"
 
@@ -16,33 +17,238 @@ func dataSourceRedshiftSchema() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
 			"owner": {
 				Type:     schema.TypeInt,
 				Optional: true,
 				Computed: true,
 			},
+			"owner_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"schema_type": { //local or external
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"quota": { //In MB, 0 if the schema has no quota
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"acl": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     aclItemSchema(),
+			},
+			"default_privileges": { //ALTER DEFAULT PRIVILEGES IN SCHEMA ...
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"owner": { //The user whose new objects get these privileges
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"object_type": { //TABLES, FUNCTIONS or PROCEDURES
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"acl": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     aclItemSchema(),
+						},
+					},
+				},
+			},
+			"tables": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"views": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"functions": { //name(argument types)
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"procedures": { //name(argument types)
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func aclItemSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"grantee": { //Empty for public
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"grantee_type": { //user, group, role or public
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"privileges": { //eg SELECT, USAGE
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 }
 
 func dataSourceRedshiftSchemaReadByName(d *schema.ResourceData, meta interface{}) error {
 	var (
-		oid   int
-		owner int
+		oid       int
+		owner     int
+		ownerName sql.NullString
+		acl       sql.NullString
 	)
 
 	name := d.Get("schema_name").(string)
-	redshiftClient := meta.(*Client).db
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
 
-	err := redshiftClient.QueryRow("select oid, nspowner from pg_namespace where nspname = $1", name).Scan(&oid, &owner)
+	err := redshiftClient.QueryRow(`select nsp.oid, nsp.nspowner, pu.usename, array_to_string(nsp.nspacl, '|')
+		from pg_namespace nsp
+		left join pg_user pu on pu.usesysid = nsp.nspowner
+		where nsp.nspname = $1`, name).Scan(&oid, &owner, &ownerName, &acl)
 
 	if err != nil {
+		return dataSourceNotFound(err, "schema", name)
+	}
+
+	aclItems, err := parseAcl(acl.String)
+	if err != nil {
+		return err
+	}
+
+	var externalSchemas int
+	if err := redshiftClient.QueryRow("select count(*) from svv_external_schemas where schemaname = $1", name).Scan(&externalSchemas); err != nil {
 		log.Print(err)
 		return err
 	}
 
+	var quota sql.NullInt64
+	err = redshiftClient.QueryRow("select quota from svv_schema_quota_state where schema_id = $1", oid).Scan(&quota)
+	if err != nil && err != sql.ErrNoRows {
+		log.Print(err)
+		return err
+	}
+
+	comment, err := readComment(redshiftClient, strconv.Itoa(oid), "pg_namespace")
+	if err != nil {
+		return err
+	}
+
+	defaultPrivileges, err := readSchemaDefaultPrivileges(redshiftClient, oid)
+	if err != nil {
+		return err
+	}
+
+	tables, err := queryStrings(redshiftClient, `select c.relname from pg_class c
+		where c.relnamespace = $1 and c.relkind = 'r' order by c.relname`, oid)
+	if err != nil {
+		return err
+	}
+	views, err := queryStrings(redshiftClient, `select c.relname from pg_class c
+		where c.relnamespace = $1 and c.relkind = 'v' order by c.relname`, oid)
+	if err != nil {
+		return err
+	}
+	functions, err := queryStrings(redshiftClient, `select p.proname || '(' || oidvectortypes(p.proargtypes) || ')' from pg_proc_info p
+		where p.pronamespace = $1 and p.prokind <> 'p' order by 1`, oid)
+	if err != nil {
+		return err
+	}
+	procedures, err := queryStrings(redshiftClient, `select p.proname || '(' || oidvectortypes(p.proargtypes) || ')' from pg_proc_info p
+		where p.pronamespace = $1 and p.prokind = 'p' order by 1`, oid)
+	if err != nil {
+		return err
+	}
+
 	d.SetId(strconv.Itoa(oid))
 	d.Set("owner", owner)
+	d.Set("owner_name", ownerName.String)
+	d.Set("acl", flattenAcl(aclItems))
+	d.Set("default_privileges", defaultPrivileges)
+	d.Set("quota", int(quota.Int64))
+	d.Set("comment", comment)
+	d.Set("tables", tables)
+	d.Set("views", views)
+	d.Set("functions", functions)
+	d.Set("procedures", procedures)
+
+	if externalSchemas > 0 {
+		d.Set("schema_type", "external")
+	} else {
+		d.Set("schema_type", "local")
+	}
+
+	return nil
+}
 
-	return err
+func readSchemaDefaultPrivileges(q Queryer, schemaOid int) ([]map[string]interface{}, error) {
+
+	rows, err := q.Query(`select pu.usename, acl.defaclobjtype, array_to_string(acl.defaclacl, '|')
+		from pg_default_acl acl
+		join pg_user pu on pu.usesysid = acl.defacluser
+		where acl.defaclnamespace = $1`, schemaOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var defaultPrivileges = []map[string]interface{}{}
+	for rows.Next() {
+		var (
+			owner      string
+			objectType string
+			acl        sql.NullString
+		)
+		if err := rows.Scan(&owner, &objectType, &acl); err != nil {
+			return nil, err
+		}
+
+		aclItems, err := parseAcl(acl.String)
+		if err != nil {
+			return nil, err
+		}
+
+		defaultPrivileges = append(defaultPrivileges, map[string]interface{}{
+			"owner":       owner,
+			"object_type": defaultAclObjectTypes[objectType],
+			"acl":         flattenAcl(aclItems),
+		})
+	}
+	return defaultPrivileges, rows.Err()
+}
+
+func flattenAcl(items []aclItem) []map[string]interface{} {
+	var flattened = []map[string]interface{}{}
+	for _, item := range items {
+		flattened = append(flattened, map[string]interface{}{
+			"grantee":      item.grantee,
+			"grantee_type": item.granteeType,
+			"privileges":   item.privilegeNames(),
+		})
+	}
+	return flattened
 }
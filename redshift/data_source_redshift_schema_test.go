@@ -0,0 +1,41 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestReadSchemaDefaultPrivileges(t *testing.T) {
+	var db = newFakeDb(fakeResult{match: "from pg_default_acl", rows: [][]driver.Value{
+		{"etl", "r", "group readers=r/etl|loader=ar/etl"},
+		{"etl", "f", "=X/etl"},
+	}})
+	defer db.Close()
+
+	defaultPrivileges, err := readSchemaDefaultPrivileges(db, 100)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected = []map[string]interface{}{
+		{
+			"owner":       "etl",
+			"object_type": "TABLES",
+			"acl": []map[string]interface{}{
+				{"grantee": "readers", "grantee_type": granteeTypeGroup, "privileges": []string{"SELECT"}},
+				{"grantee": "loader", "grantee_type": granteeTypeUser, "privileges": []string{"INSERT", "SELECT"}},
+			},
+		},
+		{
+			"owner":       "etl",
+			"object_type": "FUNCTIONS",
+			"acl": []map[string]interface{}{
+				{"grantee": "", "grantee_type": granteeTypePublic, "privileges": []string{"EXECUTE"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(defaultPrivileges, expected) {
+		t.Errorf("expected %v, got %v", expected, defaultPrivileges)
+	}
+}
@@ -0,0 +1,132 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRedshiftUser() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRedshiftUserRead,
+
+		Schema: map[string]*schema.Schema{
+			//Either username or usesysid has to be set
+			"username": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"usesysid": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"valid_until": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"createdb": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"connection_limit": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"syslog_access": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"session_timeout": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"superuser": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"parameters": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			//grosysids of the groups the user is a member of
+			"groups": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+	}
+}
+
+func dataSourceRedshiftUserRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient := meta.(*Client).db
+
+	usesysid, err := dataSourceLookupId(redshiftClient, d, "usesysid", "username", "user",
+		"SELECT usesysid FROM pg_user_info WHERE usename = $1")
+	if err != nil {
+		return err
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	d.SetId(strconv.Itoa(usesysid))
+	d.Set("usesysid", usesysid)
+
+	if err := readRedshiftUser(d, tx); err != nil {
+		tx.Rollback()
+		return dataSourceNotFound(err, "user", d.Id())
+	}
+
+	//The resource keeps the configured value for superusers, the data source reports their effective access
+	if d.Get("superuser").(bool) {
+		d.Set("syslog_access", "UNRESTRICTED")
+	}
+
+	groups, err := GetGroupIdsForUsesysid(tx, usesysid)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	d.Set("groups", groups)
+
+	tx.Commit()
+	return nil
+}
+
+// Data sources look up objects either by id or by name. Returns the id
+func dataSourceLookupId(q Queryer, d *schema.ResourceData, idAttribute string, nameAttribute string, objectType string, idForNameQuery string) (int, error) {
+
+	if v, ok := d.GetOk(idAttribute); ok {
+		return v.(int), nil
+	}
+
+	v, ok := d.GetOk(nameAttribute)
+	if !ok {
+		return -1, fmt.Errorf("Either %s or %s has to be set to look up a redshift %s", idAttribute, nameAttribute, objectType)
+	}
+
+	var id int
+	err := q.QueryRow(idForNameQuery, v.(string)).Scan(&id)
+	if err != nil {
+		return -1, dataSourceNotFound(err, objectType, v.(string))
+	}
+	return id, nil
+}
+
+func dataSourceNotFound(err error, objectType string, nameOrId string) error {
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("Could not find redshift %s %s", objectType, nameOrId)
+	}
+	log.Print(err)
+	return err
+}
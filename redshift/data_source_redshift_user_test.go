@@ -0,0 +1,38 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceRedshiftUserReadSyslogAccess(t *testing.T) {
+	cases := []struct {
+		name                 string
+		superuser            bool
+		syslogAccess         driver.Value
+		expectedSyslogAccess string
+	}{
+		{"restricted", false, nil, "RESTRICTED"},
+		{"unrestricted", false, "UNRESTRICTED", "UNRESTRICTED"},
+		{"superusers always see everything", true, nil, "UNRESTRICTED"},
+	}
+
+	for _, c := range cases {
+		var db = newFakeDb(
+			fakeResult{match: "from pg_user_info", rows: [][]driver.Value{{"etl", false, c.superuser, nil, nil, nil}}},
+			fakeResult{match: "from svv_user_info", rows: [][]driver.Value{{c.syslogAccess, nil}}},
+			fakeResult{match: "FROM pg_group", rows: nil},
+		)
+		var d = schema.TestResourceDataRaw(t, dataSourceRedshiftUser().Schema, map[string]interface{}{"usesysid": 100})
+
+		if err := dataSourceRedshiftUserRead(d, &Client{db: db}); err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if syslogAccess := d.Get("syslog_access").(string); syslogAccess != c.expectedSyslogAccess {
+			t.Errorf("%s: expected syslog_access %s, got %s", c.name, c.expectedSyslogAccess, syslogAccess)
+		}
+		db.Close()
+	}
+}
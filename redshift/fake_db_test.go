@@ -0,0 +1,131 @@
+package redshift
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A database/sql driver that answers queries with canned rows, for testing functions that take a Queryer
+// without a cluster. Each query is matched by a substring, the rows are returned whatever the arguments are.
+// Other statements succeed and are recorded in order, transactions don't do anything
+
+type fakeResult struct {
+	match string
+	rows  [][]driver.Value
+}
+
+type fakeConnector struct {
+	results    []fakeResult
+	statements *[]string
+}
+
+func newFakeDb(results ...fakeResult) *sql.DB {
+	db, _ := newRecordingFakeDb(results...)
+	return db
+}
+
+// Also returns the statements executed on the db
+func newRecordingFakeDb(results ...fakeResult) (*sql.DB, *[]string) {
+	var statements = []string{}
+	return sql.OpenDB(fakeConnector{results: results, statements: &statements}), &statements
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return fakeConn{results: c.results, statements: c.statements}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	results    []fakeResult
+	statements *[]string
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	for _, result := range c.results {
+		if strings.Contains(query, result.match) {
+			return fakeStmt{query: query, rows: result.rows, matched: true, statements: c.statements}, nil
+		}
+	}
+	return fakeStmt{query: query, statements: c.statements}, nil
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeTx struct{}
+
+func (t fakeTx) Commit() error {
+	return nil
+}
+
+func (t fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeStmt struct {
+	query      string
+	rows       [][]driver.Value
+	matched    bool
+	statements *[]string
+}
+
+func (s fakeStmt) Close() error {
+	return nil
+}
+
+func (s fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	*s.statements = append(*s.statements, s.query)
+	return driver.RowsAffected(0), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !s.matched {
+		return nil, fmt.Errorf("unexpected query %s", s.query)
+	}
+	return &fakeRows{rows: s.rows}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+	next int
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return []string{"?column?"}
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,238 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// https://docs.aws.amazon.com/redshift/latest/dg/r_DROP_USER.html
+// https://docs.aws.amazon.com/redshift/latest/dg/r_DROP_GROUP.html
+// Users and groups can't be dropped while they still have privileges on any object, so before dropping them
+// we find the objects whose acl mentions them and revoke everything on those.
+//
+// Each query returns a description of the object, the REVOKE statement up to and including FROM, to which
+// the grantee has to be appended, and the acl of the object.
+var grantedPrivilegeQueries = []string{
+	// Schemas
+	`SELECT 'schema ' || pgn.nspname,
+		'REVOKE ALL ON SCHEMA ' || QUOTE_IDENT(pgn.nspname) || ' FROM ',
+		array_to_string(pgn.nspacl, '|')
+		FROM pg_namespace pgn
+		WHERE pgn.nspacl IS NOT NULL`,
+	// Tables and views
+	`SELECT 'table ' || nc.nspname || '.' || pgc.relname,
+		'REVOKE ALL ON TABLE ' || QUOTE_IDENT(nc.nspname) || '.' || QUOTE_IDENT(pgc.relname) || ' FROM ',
+		array_to_string(pgc.relacl, '|')
+		FROM pg_class pgc, pg_namespace nc
+		WHERE pgc.relnamespace = nc.oid
+		AND   pgc.relkind IN ('r','v')
+		AND   pgc.relacl IS NOT NULL
+		AND   nc.nspname NOT ILIKE 'pg\_temp\_%'`,
+	// Functions and procedures
+	`SELECT CASE WHEN pproc.prokind = 'p' THEN 'procedure ' ELSE 'function ' END || nc.nspname || '.' || pproc.proname,
+		'REVOKE ALL ON ' || CASE WHEN pproc.prokind = 'p' THEN 'PROCEDURE ' ELSE 'FUNCTION ' END || QUOTE_IDENT(nc.nspname) || '.' || QUOTE_IDENT(pproc.proname) || '(' || oidvectortypes(pproc.proargtypes) || ') FROM ',
+		array_to_string(pproc.proacl, '|')
+		FROM pg_proc_info pproc, pg_namespace nc
+		WHERE pproc.pronamespace = nc.oid
+		AND   pproc.proacl IS NOT NULL`,
+	// Default privileges, whoever defined them
+	`SELECT 'default privileges for user ' || pgu.usename || COALESCE(' in schema ' || nc.nspname, ''),
+		'ALTER DEFAULT PRIVILEGES FOR USER ' || QUOTE_IDENT(pgu.usename) || COALESCE(' IN SCHEMA ' || QUOTE_IDENT(nc.nspname), '') ||
+		' REVOKE ALL ON ' || CASE pda.defaclobjtype WHEN 'r' THEN 'TABLES' WHEN 'f' THEN 'FUNCTIONS' ELSE 'PROCEDURES' END || ' FROM ',
+		array_to_string(pda.defaclacl, '|')
+		FROM pg_default_acl pda
+		JOIN pg_user pgu ON pda.defacluser = pgu.usesysid
+		LEFT JOIN pg_namespace nc ON pda.defaclnamespace = nc.oid
+		WHERE pda.defaclacl IS NOT NULL`,
+}
+
+// Databases are cluster wide, so their privileges are only revoked once rather than from every database
+var grantedDatabasePrivilegesQuery = `SELECT 'database ' || pgd.datname,
+	'REVOKE ALL ON DATABASE ' || QUOTE_IDENT(pgd.datname) || ' FROM ',
+	array_to_string(pgd.datacl, '|')
+	FROM pg_database pgd
+	WHERE pgd.datacl IS NOT NULL`
+
+type grantedPrivilege struct {
+	object          string
+	revokeStatement string
+}
+
+// Finds every object in the current database on which the grantee has been granted privileges.
+// Databases are only included if clusterWide is set
+func findGrantedPrivileges(q Queryer, granteeType string, grantee string, clusterWide bool) ([]grantedPrivilege, error) {
+
+	var privileges []grantedPrivilege
+
+	var queries = grantedPrivilegeQueries
+	if clusterWide {
+		queries = append(queries[:len(queries):len(queries)], grantedDatabasePrivilegesQuery)
+	}
+
+	for _, query := range queries {
+		rows, err := q.Query(query)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var (
+				object          string
+				revokeStatement string
+				acl             sql.NullString
+			)
+			if err := rows.Scan(&object, &revokeStatement, &acl); err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			items, err := parseAcl(acl.String)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			for _, item := range items {
+				if item.isGrantedTo(granteeType, grantee) {
+					privileges = append(privileges, grantedPrivilege{
+						object:          object,
+						revokeStatement: revokeStatement + quotedGranteeClause(item),
+					})
+					break
+				}
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+
+	return privileges, nil
+}
+
+// The statements that revoke all privileges granted to the grantee in the current database
+func planRevokeGrantedPrivileges(q Queryer, granteeType string, grantee string, clusterWide bool) ([]cleanupStatement, error) {
+
+	privileges, err := findGrantedPrivileges(q, granteeType, grantee, clusterWide)
+	if err != nil {
+		return nil, fmt.Errorf("Could not find privileges granted to %s %s: %s", granteeType, grantee, err)
+	}
+
+	var statements []cleanupStatement
+	for _, privilege := range privileges {
+		statements = append(statements, cleanupStatement{
+			description: "revoke privileges on " + privilege.object + " from " + granteeType + " " + grantee,
+			statement:   privilege.revokeStatement,
+		})
+	}
+	return statements, nil
+}
+
+type cleanupStatement struct {
+	description string
+	statement   string
+}
+
+// The cleanup of one database
+type databaseCleanup struct {
+	database   string
+	db         *sql.DB // A connection of its own for databases other than the provider database, closed afterwards
+	statements []cleanupStatement
+}
+
+// Users and groups are cluster wide but privileges and owned objects are per database, so cleaning up before
+// dropping them has to happen in every database, not just the one configured in the provider.
+//
+// plan finds the statements to run in a database, and fails if something can't be cleaned up. Cluster wide
+// objects, eg databases, are only handled in the provider database. Everything is discovered in every database
+// before any statement runs, so nothing is changed if something can't be cleaned up.
+//
+// The DROP only succeeds once the cleanup is visible to it, so the cleanup of each other database is committed
+// in its own transaction first, and the cleanup of the provider database runs in the transaction of the DROP.
+// This isn't atomic: if the DROP fails, the privileges already revoked and objects already transferred in other
+// databases stay that way, and the next destroy finds less to clean up.
+func dropWithCleanup(client *Client, plan func(q Queryer, clusterWide bool) ([]cleanupStatement, error), dropStatement string) error {
+
+	databases, err := client.listDatabases()
+	if err != nil {
+		return fmt.Errorf("Could not list databases: %s", err)
+	}
+
+	var cleanups = []*databaseCleanup{{database: client.config.database, db: client.db}}
+	defer func() {
+		for _, cleanup := range cleanups[1:] {
+			cleanup.db.Close()
+		}
+	}()
+
+	for _, database := range databases {
+		if database == client.config.database {
+			continue
+		}
+
+		db, err := client.config.connect(database)
+		if err != nil {
+			return fmt.Errorf("Could not connect to database %s: %s", database, err)
+		}
+		cleanups = append(cleanups, &databaseCleanup{database: database, db: db})
+	}
+
+	for i, cleanup := range cleanups {
+		log.Printf("Finding what to clean up in database %s", cleanup.database)
+
+		if cleanup.statements, err = plan(cleanup.db, i == 0); err != nil {
+			return fmt.Errorf("Could not clean up database %s: %s", cleanup.database, err)
+		}
+	}
+
+	for _, cleanup := range cleanups[1:] {
+		if len(cleanup.statements) == 0 {
+			continue
+		}
+
+		tx, err := cleanup.db.Begin()
+		if err != nil {
+			return fmt.Errorf("Could not begin redshift transaction in database %s: %s", cleanup.database, err)
+		}
+		if err := execCleanupStatements(tx, cleanup); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("Could not commit cleanup of database %s: %s", cleanup.database, err)
+		}
+	}
+
+	tx, err := client.db.Begin()
+	if err != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", err)
+	}
+	if err := execCleanupStatements(tx, cleanups[0]); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	log.Print("Drop statement: " + dropStatement)
+
+	if _, err := tx.Exec(dropStatement); err != nil {
+		tx.Rollback()
+		log.Print(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func execCleanupStatements(tx *sql.Tx, cleanup *databaseCleanup) error {
+	for _, statement := range cleanup.statements {
+		log.Print("Cleanup statement: " + statement.statement)
+
+		if _, err := tx.Exec(statement.statement); err != nil {
+			return fmt.Errorf("Could not %s in database %s: %s", statement.description, cleanup.database, err)
+		}
+	}
+	return nil
+}
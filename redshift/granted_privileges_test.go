@@ -0,0 +1,142 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func testGrantedPrivilegeResults() []fakeResult {
+	return []fakeResult{
+		{match: "FROM pg_namespace pgn", rows: [][]driver.Value{
+			{"schema etl", `REVOKE ALL ON SCHEMA "etl" FROM `, "owner=UC/owner|etl=U/owner"},
+		}},
+		{match: "FROM pg_class", rows: [][]driver.Value{
+			{"table etl.orders", `REVOKE ALL ON TABLE "etl"."orders" FROM `, "owner=arwdRxt/owner|group etl=r/owner"},
+			{"table etl.customers", `REVOKE ALL ON TABLE "etl"."customers" FROM `, "owner=arwdRxt/owner|etl=r*/owner|etl=a/other"},
+		}},
+		{match: "FROM pg_proc_info", rows: nil},
+		{match: "FROM pg_database", rows: [][]driver.Value{
+			{"database dev", `REVOKE ALL ON DATABASE "dev" FROM `, "=T/owner|owner=CT/owner|etl=T/owner"},
+		}},
+		{match: "FROM pg_default_acl", rows: [][]driver.Value{
+			{"default privileges for user owner", `ALTER DEFAULT PRIVILEGES FOR USER "owner" REVOKE ALL ON TABLES FROM `, "etl=r/owner"},
+		}},
+	}
+}
+
+func TestPlanRevokeGrantedPrivileges(t *testing.T) {
+	cases := []struct {
+		granteeType string
+		grantee     string
+		clusterWide bool
+		expected    []string
+	}{
+		{granteeTypeUser, "etl", false, []string{
+			`REVOKE ALL ON SCHEMA "etl" FROM "etl"`,
+			`REVOKE ALL ON TABLE "etl"."customers" FROM "etl"`,
+			`ALTER DEFAULT PRIVILEGES FOR USER "owner" REVOKE ALL ON TABLES FROM "etl"`,
+		}},
+		//Database privileges are only revoked once, from the provider database
+		{granteeTypeUser, "etl", true, []string{
+			`REVOKE ALL ON SCHEMA "etl" FROM "etl"`,
+			`REVOKE ALL ON TABLE "etl"."customers" FROM "etl"`,
+			`ALTER DEFAULT PRIVILEGES FOR USER "owner" REVOKE ALL ON TABLES FROM "etl"`,
+			`REVOKE ALL ON DATABASE "dev" FROM "etl"`,
+		}},
+		{granteeTypeGroup, "etl", true, []string{
+			`REVOKE ALL ON TABLE "etl"."orders" FROM GROUP "etl"`,
+		}},
+		{granteeTypeUser, "nobody", true, nil},
+	}
+
+	for _, c := range cases {
+		var db, executed = newRecordingFakeDb(testGrantedPrivilegeResults()...)
+
+		statements, err := planRevokeGrantedPrivileges(db, c.granteeType, c.grantee, c.clusterWide)
+		if err != nil {
+			t.Errorf("%s %s: err: %s", c.granteeType, c.grantee, err)
+		}
+
+		var planned []string
+		for _, statement := range statements {
+			planned = append(planned, statement.statement)
+		}
+		if !reflect.DeepEqual(planned, c.expected) {
+			t.Errorf("%s %s: expected %v, got %v", c.granteeType, c.grantee, c.expected, planned)
+		}
+		if len(*executed) > 0 {
+			t.Errorf("%s %s: expected planning to change nothing, got %v", c.granteeType, c.grantee, *executed)
+		}
+		db.Close()
+	}
+}
+
+func TestDropWithCleanup(t *testing.T) {
+	var db, executed = newRecordingFakeDb(fakeResult{match: "FROM pg_database_info", rows: [][]driver.Value{{"dev"}}})
+	defer db.Close()
+
+	var client = &Client{config: Config{database: "dev"}, db: db}
+
+	err := dropWithCleanup(client, func(q Queryer, clusterWide bool) ([]cleanupStatement, error) {
+		if !clusterWide {
+			t.Errorf("expected the provider database to be cleaned up cluster wide")
+		}
+		return []cleanupStatement{{description: "revoke", statement: `REVOKE ALL ON SCHEMA "etl" FROM "etl"`}}, nil
+	}, `DROP USER "etl"`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected = []string{`REVOKE ALL ON SCHEMA "etl" FROM "etl"`, `DROP USER "etl"`}
+	if !reflect.DeepEqual(*executed, expected) {
+		t.Errorf("expected %v, got %v", expected, *executed)
+	}
+}
+
+func TestDropWithCleanupFailedPlan(t *testing.T) {
+	var db, executed = newRecordingFakeDb(fakeResult{match: "FROM pg_database_info", rows: [][]driver.Value{{"dev"}}})
+	defer db.Close()
+
+	var client = &Client{config: Config{database: "dev"}, db: db}
+
+	err := dropWithCleanup(client, func(q Queryer, clusterWide bool) ([]cleanupStatement, error) {
+		return nil, errors.New("owns a materialized view")
+	}, `DROP USER "etl"`)
+	if err == nil {
+		t.Fatal("expected the drop to fail")
+	}
+	if len(*executed) > 0 {
+		t.Errorf("expected nothing to run, got %v", *executed)
+	}
+}
+
+func TestResourceRedshiftGroupDeleteUsesCatalogName(t *testing.T) {
+	var db, executed = newRecordingFakeDb(
+		fakeResult{match: "FROM pg_group", rows: [][]driver.Value{{"Etl"}}},
+		fakeResult{match: "FROM pg_database_info", rows: [][]driver.Value{{"dev"}}},
+		fakeResult{match: "FROM pg_namespace pgn", rows: nil},
+		fakeResult{match: "FROM pg_class", rows: [][]driver.Value{
+			{"table etl.orders", `REVOKE ALL ON TABLE "etl"."orders" FROM `, "owner=arwdRxt/owner|group Etl=r/owner"},
+		}},
+		fakeResult{match: "FROM pg_proc_info", rows: nil},
+		fakeResult{match: "FROM pg_database", rows: nil},
+		fakeResult{match: "FROM pg_default_acl", rows: nil},
+	)
+	defer db.Close()
+
+	var d = schema.TestResourceDataRaw(t, redshiftGroup().Schema, map[string]interface{}{"group_name": "etl"})
+	d.SetId("200")
+
+	if err := resourceRedshiftGroupDelete(d, &Client{config: Config{database: "dev"}, db: db}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected = []string{`REVOKE ALL ON TABLE "etl"."orders" FROM GROUP "Etl"`, `DROP GROUP "Etl"`}
+	if !reflect.DeepEqual(*executed, expected) {
+		t.Errorf("expected %v, got %v", expected, *executed)
+	}
+}
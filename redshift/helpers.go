@@ -0,0 +1,90 @@
+package redshift
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Helpers shared by the resources that build DDL from their config
+
+// schema.name of a resource with schema and name attributes
+func qualifiedName(d *schema.ResourceData) string {
+	return quoteIdentifier(d.Get("schema").(string)) + "." + quoteIdentifier(d.Get("name").(string))
+}
+
+// Uses $$ unless the body contains it
+func dollarQuote(body string) string {
+	var tag = "$$"
+	for i := 0; strings.Contains(body, tag); i++ {
+		tag = "$body" + strconv.Itoa(i) + "$"
+	}
+	return tag + body + tag
+}
+
+// Names from the config are validated as lowercase, so quoting them doesn't change which object they refer to
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+// Editors and heredocs add leading and trailing newlines that don't change a body or expression
+func suppressSurroundingWhitespaceDiff(k, old, new string, d *schema.ResourceData) bool {
+	return strings.TrimSpace(old) == strings.TrimSpace(new)
+}
+
+// Keywords like DEFINER or AND are stored uppercase whatever the config uses
+func upperCaseStateFunc(v interface{}) string {
+	return strings.ToUpper(v.(string))
+}
+
+// Hashes of text Redshift stores, to tell whether it changed outside terraform
+func sha256Hex(s string) string {
+	var hash = sha256.Sum256([]byte(s))
+	return hex.EncodeToString(hash[:])
+}
+
+// Names are quoted in DDL but looked up as they are in the catalogs, which hold them lowercase unless
+// case sensitive identifiers are enabled, so mixed case names would never be found again
+func validateLowercaseName(v interface{}, k string) (ws []string, errors []error) {
+	if name := v.(string); name != strings.ToLower(name) {
+		errors = append(errors, fmt.Errorf("%s must be lowercase, got %s", k, name))
+	}
+	return
+}
+
+// Reads the first column of all rows
+func queryStrings(q Queryer, query string, args ...interface{}) ([]string, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values = []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// Quoted, comma separated column names
+func quotedColumnList(columns []interface{}) string {
+	var quoted []string
+	for _, column := range columns {
+		quoted = append(quoted, quoteIdentifier(column.(string)))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// schema.table of a resource with schema and table attributes
+func qualifiedTableName(d *schema.ResourceData) string {
+	return quoteIdentifier(d.Get("schema").(string)) + "." + quoteIdentifier(d.Get("table").(string))
+}
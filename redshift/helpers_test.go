@@ -0,0 +1,72 @@
+package redshift
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDollarQuote(t *testing.T) {
+	cases := map[string]string{
+		"":                        "$$$$",
+		"select 1":                "$$select 1$$",
+		"select '$'":              "$$select '$'$$",
+		"select $$a$$":            "$body0$select $$a$$$body0$",
+		"select $$a$$ $body0$":    "$body1$select $$a$$ $body0$$body1$",
+		"return 'it''s' || $1;":   "$$return 'it''s' || $1;$$",
+		"python:\n  return 'x'\n": "$$python:\n  return 'x'\n$$",
+	}
+
+	for body, expected := range cases {
+		if quoted := dollarQuote(body); quoted != expected {
+			t.Errorf("dollarQuote(%q): expected %q, got %q", body, expected, quoted)
+		}
+	}
+}
+
+func TestValidateLowercaseName(t *testing.T) {
+	for _, name := range []string{"", "etl", "etl_orders_2", "tenant rows"} {
+		if _, errors := validateLowercaseName(name, "name"); len(errors) > 0 {
+			t.Errorf("expected %s to be valid, got %v", name, errors)
+		}
+	}
+	for _, name := range []string{"Etl", "ORDERS"} {
+		if _, errors := validateLowercaseName(name, "name"); len(errors) == 0 {
+			t.Errorf("expected %s to be invalid", name)
+		}
+	}
+}
+
+func TestNormalizeArgumentType(t *testing.T) {
+	cases := map[string]string{
+		"int":                      "integer",
+		" INTEGER ":                "integer",
+		"VARCHAR(256)":             "character varying",
+		"character  varying(10)":   "character varying",
+		"numeric(10, 2)":           "numeric",
+		"decimal":                  "numeric",
+		"TIMESTAMP":                "timestamp without time zone",
+		"timestamp with time zone": "timestamp with time zone",
+		"float8":                   "double precision",
+		"anyelement":               "anyelement",
+	}
+
+	for argumentType, expected := range cases {
+		if normalized := normalizeArgumentType(argumentType); normalized != expected {
+			t.Errorf("normalizeArgumentType(%q): expected %q, got %q", argumentType, expected, normalized)
+		}
+	}
+}
+
+func TestRoutineInputTypes(t *testing.T) {
+	var arguments = []interface{}{
+		map[string]interface{}{"name": "a", "type": "INT", "mode": "IN"},
+		map[string]interface{}{"name": "b", "type": "varchar(10)", "mode": "INOUT"},
+		map[string]interface{}{"name": "c", "type": "bool", "mode": "OUT"},
+		map[string]interface{}{"name": "d", "type": "float"},
+	}
+
+	var expected = []string{"integer", "character varying", "double precision"}
+	if types := routineInputTypes(arguments); !reflect.DeepEqual(types, expected) {
+		t.Errorf("expected %v, got %v", expected, types)
+	}
+}
@@ -0,0 +1,327 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// https://docs.aws.amazon.com/redshift/latest/dg/r_DROP_USER.html
+// If a user owns an object, first drop the object or change its ownership to another user before dropping
+// the original user.
+//
+// Derived from https://github.com/awslabs/amazon-redshift-utils/blob/master/src/AdminViews/v_find_dropuser_objs.sql
+//
+// Each query returns the object type, the usesysid of the owner, the schema (empty for cluster wide objects),
+// the object name and the statement that transfers it, to which the new owner has to be appended.
+// The statement is null if ownership of the object can't be transferred and it has to be dropped instead.
+//
+// System tables (pg_*) can't be joined with stv_ and svv_ tables as they only exist on the leader node,
+// which is why these are separate queries rather than a union.
+var ownedObjectQueries = []string{
+	// Functions and procedures
+	`SELECT CASE WHEN pproc.prokind = 'p' THEN 'procedure' ELSE 'function' END,
+		pproc.proowner,
+		nc.nspname,
+		pproc.proname || '(' || oidvectortypes(pproc.proargtypes) || ')',
+		'alter ' || CASE WHEN pproc.prokind = 'p' THEN 'procedure ' ELSE 'function ' END || QUOTE_IDENT(nc.nspname) || '.' || QUOTE_IDENT(pproc.proname) || '(' || oidvectortypes(pproc.proargtypes) || ') owner to '
+		FROM pg_proc_info pproc, pg_namespace nc
+		WHERE pproc.pronamespace = nc.oid`,
+	// Schemas
+	`SELECT 'schema', pgn.nspowner, pgn.nspname, pgn.nspname,
+		'alter schema ' || QUOTE_IDENT(pgn.nspname) || ' owner to '
+		FROM pg_namespace pgn`,
+	// Tables and views. Materialized views show up here as views too, they are filtered out below
+	`SELECT CASE WHEN pgc.relkind = 'r' THEN 'table' ELSE 'view' END,
+		pgc.relowner,
+		nc.nspname,
+		pgc.relname,
+		'alter table ' || QUOTE_IDENT(nc.nspname) || '.' || QUOTE_IDENT(pgc.relname) || ' owner to '
+		FROM pg_class pgc, pg_namespace nc
+		WHERE pgc.relnamespace = nc.oid
+		AND   pgc.relkind IN ('r','v')
+		AND   nc.nspname NOT ILIKE 'pg\_temp\_%'`,
+	// Python libraries
+	`SELECT 'library', pgl.owner, '', pgl.name, NULL
+		FROM pg_library pgl`,
+}
+
+// Databases are cluster wide, so they are only transferred once rather than from every database
+var ownedDatabasesQuery = `SELECT 'database', pgd.datdba, '', pgd.datname,
+	'alter database ' || QUOTE_IDENT(pgd.datname) || ' owner to '
+	FROM pg_database pgd`
+
+// Materialized views only record the owner by name
+var materializedViewsQuery = `SELECT mv.schema, mv.name, mv.owner_user_name
+	FROM stv_mv_info mv
+	WHERE mv.db_name = current_database()`
+
+var datasharesQuery = `SELECT share_owner, share_name
+	FROM svv_datashares
+	WHERE share_type = 'OUTBOUND'`
+
+// Default privileges a user has defined with ALTER DEFAULT PRIVILEGES FOR USER
+var defaultPrivilegesQuery = `SELECT pda.defacluser, pgu.usename, COALESCE(nc.nspname, ''), pda.defaclobjtype, array_to_string(pda.defaclacl, '|')
+	FROM pg_default_acl pda
+	JOIN pg_user pgu ON pda.defacluser = pgu.usesysid
+	LEFT JOIN pg_namespace nc ON pda.defaclnamespace = nc.oid`
+
+const defaultPrivilegesObjectType = "default privileges"
+
+type ownedObject struct {
+	objectType string
+	owner      int
+	schemaName string
+	name       string
+	// The new owner has to be appended. Empty if ownership can't be transferred
+	reassignStatement string
+	// Only for default privileges, which can't be transferred but can be revoked
+	revokeStatements []string
+}
+
+func (o ownedObject) String() string {
+	if o.schemaName == "" || o.objectType == "schema" {
+		return o.objectType + " " + o.name
+	}
+	return o.objectType + " " + o.schemaName + "." + o.name
+}
+
+// Finds all objects in the current database that are owned by the users for which owned returns true.
+// Databases are only included if clusterWide is set
+func findOwnedObjects(q Queryer, clusterWide bool, owned func(ownedObject) bool) ([]ownedObject, error) {
+
+	var objects []ownedObject
+
+	materializedViewObjects, err := findMaterializedViewObjects(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var materializedViews = make(map[string]bool)
+	for _, object := range materializedViewObjects {
+		materializedViews[object.schemaName+"."+object.name] = true
+	}
+
+	var queries = ownedObjectQueries
+	if clusterWide {
+		queries = append(queries[:len(queries):len(queries)], ownedDatabasesQuery)
+	}
+
+	for _, query := range queries {
+		rows, err := q.Query(query)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var (
+				object            ownedObject
+				reassignStatement sql.NullString
+			)
+			if err := rows.Scan(&object.objectType, &object.owner, &object.schemaName, &object.name, &reassignStatement); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			object.reassignStatement = reassignStatement.String
+
+			if object.objectType == "view" && materializedViews[object.schemaName+"."+object.name] {
+				continue
+			}
+			if owned(object) {
+				objects = append(objects, object)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+
+	datashares, err := findDatashares(q)
+	if err != nil {
+		return nil, err
+	}
+	defaultPrivileges, err := findDefaultPrivileges(q)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, object := range append(append(materializedViewObjects, datashares...), defaultPrivileges...) {
+		if owned(object) {
+			objects = append(objects, object)
+		}
+	}
+
+	return objects, nil
+}
+
+// Ownership of materialized views can't be transferred, they have to be dropped and recreated
+func findMaterializedViewObjects(q Queryer) ([]ownedObject, error) {
+	usesysids, err := getUsesysidsByName(q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(materializedViewsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []ownedObject
+	for rows.Next() {
+		var (
+			object    = ownedObject{objectType: "materialized view"}
+			ownerName string
+		)
+		if err := rows.Scan(&object.schemaName, &object.name, &ownerName); err != nil {
+			return nil, err
+		}
+		object.schemaName = strings.TrimSpace(object.schemaName)
+		object.name = strings.TrimSpace(object.name)
+		object.owner = usesysids[strings.TrimSpace(ownerName)]
+		objects = append(objects, object)
+	}
+	return objects, rows.Err()
+}
+
+// Ownership of datashares can't be transferred either
+func findDatashares(q Queryer) ([]ownedObject, error) {
+	rows, err := q.Query(datasharesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []ownedObject
+	for rows.Next() {
+		var object = ownedObject{objectType: "datashare"}
+		if err := rows.Scan(&object.owner, &object.name); err != nil {
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	return objects, rows.Err()
+}
+
+// https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_DEFAULT_PRIVILEGES.html
+// A user can't be dropped while default privileges defined for them exist, so they are revoked from every grantee
+func findDefaultPrivileges(q Queryer) ([]ownedObject, error) {
+	rows, err := q.Query(defaultPrivilegesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []ownedObject
+	for rows.Next() {
+		var (
+			object        = ownedObject{objectType: defaultPrivilegesObjectType}
+			ownerName     string
+			objectTypeKey string
+			acl           sql.NullString
+		)
+		if err := rows.Scan(&object.owner, &ownerName, &object.schemaName, &objectTypeKey, &acl); err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if object.name, ok = defaultAclObjectTypes[objectTypeKey]; !ok {
+			return nil, fmt.Errorf("Could not revoke default privileges of %s on unknown object type %s", ownerName, objectTypeKey)
+		}
+
+		aclItems, err := parseAcl(acl.String)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range aclItems {
+			var revokeStatement = "ALTER DEFAULT PRIVILEGES FOR USER " + ownerName
+			if object.schemaName != "" {
+				revokeStatement += " IN SCHEMA " + object.schemaName
+			}
+			revokeStatement += " REVOKE ALL ON " + object.name + " FROM " + item.granteeClause()
+			object.revokeStatements = append(object.revokeStatements, revokeStatement)
+		}
+		objects = append(objects, object)
+	}
+	return objects, rows.Err()
+}
+
+var defaultAclObjectTypes = map[string]string{
+	"r": "TABLES",
+	"f": "FUNCTIONS",
+	"p": "PROCEDURES",
+}
+
+func getUsesysidsByName(q Queryer) (map[string]int, error) {
+	rows, err := q.Query("SELECT usename, usesysid FROM pg_user")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usesysids = make(map[string]int)
+	for rows.Next() {
+		var (
+			usename  string
+			usesysid int
+		)
+		if err := rows.Scan(&usename, &usesysid); err != nil {
+			return nil, err
+		}
+		usesysids[usename] = usesysid
+	}
+	return usesysids, rows.Err()
+}
+
+// The statements that transfer every object owned by the user in the current database to newOwner, and revoke
+// the default privileges they have defined. Fails if some objects can't be transferred, or if failIfOwnsObjects
+// is set and the user owns anything at all
+func planReassignOwnedObjects(q Queryer, usesysid int, newOwner string, failIfOwnsObjects bool, clusterWide bool) ([]cleanupStatement, error) {
+
+	objects, err := findOwnedObjects(q, clusterWide, func(o ownedObject) bool {
+		return o.owner == usesysid
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if failIfOwnsObjects && len(objects) > 0 {
+		return nil, fmt.Errorf("User still owns objects: %s", describeOwnedObjects(objects))
+	}
+
+	var notTransferable []ownedObject
+	for _, object := range objects {
+		if object.reassignStatement == "" && object.objectType != defaultPrivilegesObjectType {
+			notTransferable = append(notTransferable, object)
+		}
+	}
+	if len(notTransferable) > 0 {
+		return nil, fmt.Errorf("User owns objects that can't be transferred to %s, they have to be dropped first: %s",
+			newOwner, describeOwnedObjects(notTransferable))
+	}
+
+	var statements []cleanupStatement
+	for _, object := range objects {
+		for _, statement := range object.revokeStatements {
+			statements = append(statements, cleanupStatement{description: "revoke " + object.String(), statement: statement})
+		}
+		if object.reassignStatement != "" {
+			statements = append(statements, cleanupStatement{
+				description: "transfer " + object.String() + " to " + newOwner,
+				statement:   object.reassignStatement + newOwner,
+			})
+		}
+	}
+	return statements, nil
+}
+
+func describeOwnedObjects(objects []ownedObject) string {
+	var descriptions []string
+	for _, object := range objects {
+		descriptions = append(descriptions, object.String())
+	}
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,136 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindDefaultPrivileges(t *testing.T) {
+	var db = newFakeDb(fakeResult{match: "pg_default_acl", rows: [][]driver.Value{
+		{int64(100), "etl", "analytics", "r", "group readers=r/etl"},
+		{int64(100), "etl", "", "f", "=X/etl"},
+	}})
+	defer db.Close()
+
+	objects, err := findDefaultPrivileges(db)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected = []ownedObject{
+		{
+			objectType:       defaultPrivilegesObjectType,
+			owner:            100,
+			schemaName:       "analytics",
+			name:             "TABLES",
+			revokeStatements: []string{"ALTER DEFAULT PRIVILEGES FOR USER etl IN SCHEMA analytics REVOKE ALL ON TABLES FROM GROUP readers"},
+		},
+		{
+			objectType:       defaultPrivilegesObjectType,
+			owner:            100,
+			name:             "FUNCTIONS",
+			revokeStatements: []string{"ALTER DEFAULT PRIVILEGES FOR USER etl REVOKE ALL ON FUNCTIONS FROM PUBLIC"},
+		},
+	}
+	if !reflect.DeepEqual(objects, expected) {
+		t.Errorf("expected %v, got %v", expected, objects)
+	}
+}
+
+func TestFindDefaultPrivilegesUnknownObjectType(t *testing.T) {
+	var db = newFakeDb(fakeResult{match: "pg_default_acl", rows: [][]driver.Value{
+		{int64(100), "etl", "analytics", "S", "group readers=r/etl"},
+	}})
+	defer db.Close()
+
+	if objects, err := findDefaultPrivileges(db); err == nil {
+		t.Errorf("expected an error, got %v", objects)
+	}
+}
+
+// Objects of user 100, the database included
+func testOwnedObjectResults(materializedViews [][]driver.Value) []fakeResult {
+	return []fakeResult{
+		{match: "FROM pg_proc_info", rows: [][]driver.Value{{"function", int64(100), "etl", "f(integer)", "alter function etl.f(integer) owner to "}}},
+		{match: "FROM pg_database", rows: [][]driver.Value{{"database", int64(100), "", "dev", "alter database dev owner to "}}},
+		{match: "FROM pg_namespace pgn", rows: [][]driver.Value{{"schema", int64(100), "etl", "etl", "alter schema etl owner to "}}},
+		{match: "FROM pg_class", rows: [][]driver.Value{
+			{"table", int64(100), "etl", "orders", "alter table etl.orders owner to "},
+			{"view", int64(100), "etl", "daily_orders", "alter table etl.daily_orders owner to "},
+		}},
+		{match: "FROM pg_library", rows: nil},
+		{match: "FROM stv_mv_info", rows: materializedViews},
+		{match: "FROM svv_datashares", rows: nil},
+		{match: "FROM pg_default_acl", rows: [][]driver.Value{{int64(100), "etl", "etl", "r", "group readers=r/etl"}}},
+		{match: "FROM pg_user", rows: [][]driver.Value{{"etl", int64(100)}, {"other", int64(101)}}},
+	}
+}
+
+func TestPlanReassignOwnedObjects(t *testing.T) {
+	cases := []struct {
+		clusterWide bool
+		expected    []string
+	}{
+		{false, []string{
+			"alter function etl.f(integer) owner to admin",
+			"alter schema etl owner to admin",
+			"alter table etl.orders owner to admin",
+			"alter table etl.daily_orders owner to admin",
+			"ALTER DEFAULT PRIVILEGES FOR USER etl IN SCHEMA etl REVOKE ALL ON TABLES FROM GROUP readers",
+		}},
+		//The database is only transferred once, from the provider database
+		{true, []string{
+			"alter function etl.f(integer) owner to admin",
+			"alter schema etl owner to admin",
+			"alter table etl.orders owner to admin",
+			"alter table etl.daily_orders owner to admin",
+			"alter database dev owner to admin",
+			"ALTER DEFAULT PRIVILEGES FOR USER etl IN SCHEMA etl REVOKE ALL ON TABLES FROM GROUP readers",
+		}},
+	}
+
+	for _, c := range cases {
+		var db, executed = newRecordingFakeDb(testOwnedObjectResults(nil)...)
+
+		statements, err := planReassignOwnedObjects(db, 100, "admin", false, c.clusterWide)
+		if err != nil {
+			t.Fatalf("cluster wide %t: err: %s", c.clusterWide, err)
+		}
+
+		var planned []string
+		for _, statement := range statements {
+			planned = append(planned, statement.statement)
+		}
+		if !reflect.DeepEqual(planned, c.expected) {
+			t.Errorf("cluster wide %t: expected %v, got %v", c.clusterWide, c.expected, planned)
+		}
+		if len(*executed) > 0 {
+			t.Errorf("cluster wide %t: expected planning to change nothing, got %v", c.clusterWide, *executed)
+		}
+		db.Close()
+	}
+}
+
+func TestPlanReassignOwnedObjectsFails(t *testing.T) {
+	cases := []struct {
+		name              string
+		materializedViews [][]driver.Value
+		failIfOwnsObjects bool
+		expected          string
+	}{
+		{"owns objects", nil, true, "table etl.orders"},
+		{"materialized views can't be transferred", [][]driver.Value{{"etl ", "daily_orders ", "etl "}}, false, "materialized view etl.daily_orders"},
+	}
+
+	for _, c := range cases {
+		var db = newFakeDb(testOwnedObjectResults(c.materializedViews)...)
+
+		_, err := planReassignOwnedObjects(db, 100, "admin", c.failIfOwnsObjects, false)
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("%s: expected an error about %s, got %v", c.name, c.expected, err)
+		}
+		db.Close()
+	}
+}
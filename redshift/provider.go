@@ -47,14 +47,38 @@ func Provider() terraform.ResourceProvider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"redshift_user":                   redshiftUser(),
-			"redshift_group":                  redshiftGroup(),
-			"redshift_database":               redshiftDatabase(),
-			"redshift_schema":                 redshiftSchema(),
-			"redshift_group_schema_privilege": redshiftSchemaGroupPrivilege(),
+			"redshift_user":                      redshiftUser(),
+			"redshift_group":                     redshiftGroup(),
+			"redshift_group_membership":          redshiftGroupMembership(),
+			"redshift_database":                  redshiftDatabase(),
+			"redshift_schema":                    redshiftSchema(),
+			"redshift_group_schema_privilege":    redshiftSchemaGroupPrivilege(),
+			"redshift_sql":                       redshiftSql(),
+			"redshift_procedure":                 redshiftProcedure(),
+			"redshift_function":                  redshiftFunction(),
+			"redshift_view":                      redshiftView(),
+			"redshift_library":                   redshiftLibrary(),
+			"redshift_schema_ownership":          redshiftSchemaOwnership(),
+			"redshift_schema_privileges":         redshiftSchemaPrivileges(),
+			"redshift_rls_policy":                redshiftRlsPolicy(),
+			"redshift_rls_policy_attachment":     redshiftRlsPolicyAttachment(),
+			"redshift_table_rls":                 redshiftTableRls(),
+			"redshift_masking_policy":            redshiftMaskingPolicy(),
+			"redshift_masking_policy_attachment": redshiftMaskingPolicyAttachment(),
+			"redshift_column_privilege":          redshiftColumnPrivilege(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"redshift_schema": dataSourceRedshiftSchema(),
+			"redshift_schema":               dataSourceRedshiftSchema(),
+			"redshift_user":                 dataSourceRedshiftUser(),
+			"redshift_users":                dataSourceRedshiftUsers(),
+			"redshift_group":                dataSourceRedshiftGroup(),
+			"redshift_groups":               dataSourceRedshiftGroups(),
+			"redshift_database":             dataSourceRedshiftDatabase(),
+			"redshift_databases":            dataSourceRedshiftDatabases(),
+			"redshift_role":                 dataSourceRedshiftRole(),
+			"redshift_roles":                dataSourceRedshiftRoles(),
+			"redshift_effective_privileges": dataSourceRedshiftEffectivePrivileges(),
+			"redshift_query":                dataSourceRedshiftQuery(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
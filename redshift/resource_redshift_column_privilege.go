@@ -0,0 +1,288 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_SVV_COLUMN_PRIVILEGES.html
+
+/*
+Column level SELECT and UPDATE privileges on one table for one user, group or role, eg to only expose the
+non sensitive columns of a table. Columns removed from the lists are revoked.
+Privileges on the whole table, granted with GRANT SELECT ON table, are not managed here.
+The id is schema.table:user:usesysid, schema.table:group:grosysid or schema.table:role:role_id.
+*/
+func redshiftColumnPrivilege() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftColumnPrivilegeCreate,
+		Read:   resourceRedshiftColumnPrivilegeRead,
+		Update: resourceRedshiftColumnPrivilegeUpdate,
+		Delete: resourceRedshiftColumnPrivilegeDelete,
+
+		Schema: map[string]*schema.Schema{
+			"schema": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"table": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			//Exactly one of user_id, group_id and role_id has to be set
+			"user_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"group_id", "role_id"},
+			},
+			"group_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"user_id", "role_id"},
+			},
+			"role_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"user_id", "group_id"},
+			},
+			"select_columns": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString, ValidateFunc: validateLowercaseName},
+			},
+			"update_columns": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString, ValidateFunc: validateLowercaseName},
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+var columnPrivilegeAttributes = map[string]string{
+	"select_columns": "SELECT",
+	"update_columns": "UPDATE",
+}
+
+// The user, group or role the columns are granted to
+type columnPrivilegeGrantee struct {
+	identityType string // user, group or role, as in svv_column_privileges
+	id           int
+	name         string
+}
+
+func (g columnPrivilegeGrantee) clause() string {
+	switch g.identityType {
+	case granteeTypeGroup:
+		return "GROUP " + quoteIdentifier(g.name)
+	case granteeTypeRole:
+		return "ROLE " + quoteIdentifier(g.name)
+	default:
+		return quoteIdentifier(g.name)
+	}
+}
+
+func resolveColumnPrivilegeGrantee(q Queryer, d *schema.ResourceData) (columnPrivilegeGrantee, error) {
+
+	var grantee columnPrivilegeGrantee
+	var err error
+
+	if v, ok := d.GetOk("user_id"); ok {
+		grantee = columnPrivilegeGrantee{identityType: granteeTypeUser, id: v.(int)}
+		err = q.QueryRow("SELECT usename FROM pg_user_info WHERE usesysid = $1", grantee.id).Scan(&grantee.name)
+	} else if v, ok := d.GetOk("group_id"); ok {
+		grantee = columnPrivilegeGrantee{identityType: granteeTypeGroup, id: v.(int)}
+		grantee.name, err = GetGroupNameForGroupId(q, grantee.id)
+	} else if v, ok := d.GetOk("role_id"); ok {
+		grantee = columnPrivilegeGrantee{identityType: granteeTypeRole, id: v.(int)}
+		err = q.QueryRow("SELECT role_name FROM svv_roles WHERE role_id = $1", grantee.id).Scan(&grantee.name)
+	} else {
+		return grantee, NewError("One of user_id, group_id or role_id has to be set")
+	}
+	return grantee, err
+}
+
+func resourceRedshiftColumnPrivilegeCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	grantee, err := resolveColumnPrivilegeGrantee(tx, d)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for attribute, privilege := range columnPrivilegeAttributes {
+		if err := alterColumnPrivileges(tx, d, "GRANT", privilege, d.Get(attribute).(*schema.Set).List(), grantee); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	d.SetId(d.Get("schema").(string) + "." + d.Get("table").(string) + ":" + grantee.identityType + ":" + strconv.Itoa(grantee.id))
+
+	return resourceRedshiftColumnPrivilegeRead(d, meta)
+}
+
+func resourceRedshiftColumnPrivilegeRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	grantee, err := resolveColumnPrivilegeGrantee(redshiftClient, d)
+	if err == sql.ErrNoRows {
+		//The grantee has been dropped, and their privileges with them
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for attribute, privilege := range columnPrivilegeAttributes {
+		columns, err := queryStrings(redshiftClient, `SELECT column_name FROM svv_column_privileges
+			WHERE namespace_name = $1 AND relation_name = $2 AND privilege_type = $3 AND identity_type = $4 AND identity_id = $5`,
+			d.Get("schema").(string), d.Get("table").(string), privilege, grantee.identityType, grantee.id)
+		if err != nil {
+			log.Print(err)
+			return err
+		}
+		d.Set(attribute, columns)
+	}
+
+	return nil
+}
+
+func resourceRedshiftColumnPrivilegeUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	grantee, err := resolveColumnPrivilegeGrantee(tx, d)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for attribute, privilege := range columnPrivilegeAttributes {
+		if !d.HasChange(attribute) {
+			continue
+		}
+
+		oldColumns, newColumns := d.GetChange(attribute)
+
+		var (
+			columnsRemoved = oldColumns.(*schema.Set).Difference(newColumns.(*schema.Set)).List()
+			columnsAdded   = newColumns.(*schema.Set).Difference(oldColumns.(*schema.Set)).List()
+		)
+
+		if err := alterColumnPrivileges(tx, d, "REVOKE", privilege, columnsRemoved, grantee); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := alterColumnPrivileges(tx, d, "GRANT", privilege, columnsAdded, grantee); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return resourceRedshiftColumnPrivilegeRead(d, meta)
+}
+
+func resourceRedshiftColumnPrivilegeDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	grantee, err := resolveColumnPrivilegeGrantee(tx, d)
+	if err == sql.ErrNoRows {
+		//The grantee has been dropped, and their privileges with them
+		tx.Rollback()
+		return nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for attribute, privilege := range columnPrivilegeAttributes {
+		if err := alterColumnPrivileges(tx, d, "REVOKE", privilege, d.Get(attribute).(*schema.Set).List(), grantee); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// action is GRANT or REVOKE, privilege is SELECT or UPDATE
+func alterColumnPrivileges(tx *sql.Tx, d *schema.ResourceData, action string, privilege string, columns []interface{}, grantee columnPrivilegeGrantee) error {
+
+	if len(columns) == 0 {
+		return nil
+	}
+
+	var statement = action + " " + privilege + " (" + quotedColumnList(columns) + ") ON " + qualifiedTableName(d)
+	if action == "GRANT" {
+		statement += " TO " + grantee.clause()
+	} else {
+		statement += " FROM " + grantee.clause()
+	}
+
+	log.Print("Column privilege statement: " + statement)
+
+	if _, err := tx.Exec(statement); err != nil {
+		return fmt.Errorf("Could not %s %s on columns %s: %s", strings.ToLower(action), privilege, quotedColumnList(columns), err)
+	}
+	return nil
+}
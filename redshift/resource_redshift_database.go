@@ -6,6 +6,7 @@ package redshift
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"time"
@@ -37,6 +38,10 @@ func redshiftDatabase() *schema.Resource {
 				Optional: true,
 				Default:  "UNLIMITED",
 			},
+			"comment": { //Set with COMMENT ON DATABASE
+				Type:     schema.TypeString,
+				Optional: true,
+			},
 		},
 	}
 }
@@ -66,8 +71,11 @@ func resourceRedshiftDatabaseCreate(d *schema.ResourceData, meta interface{}) er
 
 	//If no owner is specified it defaults to client user
 	if v, ok := d.GetOk("owner"); ok {
-		var usernames = GetUsersnamesForUsesysid(redshiftClient, []interface{}{v.(int)})
-		createStatement += " OWNER " + usernames[0]
+		username, err := GetUsernameForUsesysid(redshiftClient, v.(int))
+		if err != nil {
+			return fmt.Errorf("Could not find redshift user %d: %s", v.(int), err)
+		}
+		createStatement += " OWNER " + username
 	}
 
 	if v, ok := d.GetOk("connection_limit"); ok {
@@ -94,36 +102,48 @@ func resourceRedshiftDatabaseCreate(d *schema.ResourceData, meta interface{}) er
 
 	d.SetId(datid)
 
-	readErr := readRedshiftDatabase(d, redshiftClient)
+	if v, ok := d.GetOk("comment"); ok {
+		if err := setDatabaseComment(meta.(*Client), d.Get("database_name").(string), v.(string)); err != nil {
+			return err
+		}
+	}
+
+	readErr := readRedshiftDatabase(d, meta.(*Client))
 
 	return readErr
 }
 
 func resourceRedshiftDatabaseRead(d *schema.ResourceData, meta interface{}) error {
 
-	redshiftClient := meta.(*Client).db
-
-	err := readRedshiftDatabase(d, redshiftClient)
+	err := readRedshiftDatabase(d, meta.(*Client))
 
 	return err
 }
 
-func readRedshiftDatabase(d *schema.ResourceData, db *sql.DB) error {
+func readRedshiftDatabase(d *schema.ResourceData, client *Client) error {
 	var (
 		databasename string
 		owner        int
 		connlimit    sql.NullString
 	)
 
-	err := db.QueryRow("select datname, datdba, datconnlimit from pg_database_info where datid = $1", d.Id()).Scan(&databasename, &owner, &connlimit)
+	err := client.db.QueryRow("select datname, datdba, datconnlimit from pg_database_info where datid = $1", d.Id()).Scan(&databasename, &owner, &connlimit)
 
 	if err != nil {
 		log.Print(err)
 		return err
 	}
 
+	//The name from the catalog rather than the config, so a database renamed outside terraform is still found
+	comment, err := readDatabaseComment(client, databasename, d.Id())
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
 	d.Set("database_name", databasename)
 	d.Set("owner", owner)
+	d.Set("comment", comment)
 
 	if connlimit.Valid {
 		d.Set("connection_limit", connlimit.String)
@@ -147,16 +167,27 @@ func resourceRedshiftDatabaseUpdate(d *schema.ResourceData, meta interface{}) er
 		oldName, newName := d.GetChange("database_name")
 		alterDatabaseNameQuery := "ALTER DATABASE " + oldName.(string) + " rename to " + newName.(string)
 
+		//A database can't be renamed while other sessions are connected to it
+		if err := meta.(*Client).Disconnect(oldName.(string)); err != nil {
+			log.Print(err)
+		}
+
 		if _, err := tx.Exec(alterDatabaseNameQuery); err != nil {
+			tx.Rollback()
 			return err
 		}
 	}
 
 	if d.HasChange("owner") {
 
-		var username = GetUsersnamesForUsesysid(redshiftClient, []interface{}{d.Get("owner").(int)})
+		username, err := GetUsernameForUsesysid(redshiftClient, d.Get("owner").(int))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Could not find redshift user %d: %s", d.Get("owner").(int), err)
+		}
 
-		if _, err := tx.Exec("ALTER DATABASE " + d.Get("database_name").(string) + " OWNER TO " + username[0]); err != nil {
+		if _, err := tx.Exec("ALTER DATABASE " + d.Get("database_name").(string) + " OWNER TO " + username); err != nil {
+			tx.Rollback()
 			return err
 		}
 	}
@@ -168,21 +199,29 @@ func resourceRedshiftDatabaseUpdate(d *schema.ResourceData, meta interface{}) er
 		}
 	}
 
-	err := readRedshiftDatabase(d, redshiftClient)
-
-	if err != nil {
-		tx.Rollback()
+	//The rename has to be committed before connecting to the database under its new name
+	if err := tx.Commit(); err != nil {
 		return err
 	}
 
-	tx.Commit()
-	return nil
+	if d.HasChange("comment") {
+		if err := setDatabaseComment(meta.(*Client), d.Get("database_name").(string), d.Get("comment").(string)); err != nil {
+			return err
+		}
+	}
+
+	return readRedshiftDatabase(d, meta.(*Client))
 }
 
 func resourceRedshiftDatabaseDelete(d *schema.ResourceData, meta interface{}) error {
 
 	client := meta.(*Client).db
 
+	//A database can't be dropped while other sessions are connected to it, eg from resources in it
+	if err := meta.(*Client).Disconnect(d.Get("database_name").(string)); err != nil {
+		log.Print(err)
+	}
+
 	_, err := client.Exec("drop database " + d.Get("database_name").(string))
 
 	if err != nil {
@@ -194,8 +233,37 @@ func resourceRedshiftDatabaseDelete(d *schema.ResourceData, meta interface{}) er
 }
 
 func resourceRedshiftDatabaseImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	datid, err := resolveImportId(meta.(*Client).db, d.Id(), "database",
+		"SELECT datid, datname FROM pg_database_info WHERE lower(datname) = lower($1)")
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(datid)
+
 	if err := resourceRedshiftDatabaseRead(d, meta); err != nil {
 		return nil, err
 	}
 	return []*schema.ResourceData{d}, nil
 }
+
+// Database comments are stored in the database itself, and can only be set while connected to it
+func setDatabaseComment(client *Client, databaseName string, comment string) error {
+
+	db, err := client.Connect(databaseName)
+	if err != nil {
+		return fmt.Errorf("Could not connect to database %s to set its comment: %s", databaseName, err)
+	}
+	if err := setComment(db, "DATABASE", databaseName, comment); err != nil {
+		return fmt.Errorf("Could not set comment on database %s: %s", databaseName, err)
+	}
+	return nil
+}
+
+func readDatabaseComment(client *Client, databaseName string, datid string) (string, error) {
+
+	db, err := client.Connect(databaseName)
+	if err != nil {
+		return "", fmt.Errorf("Could not connect to database %s to read its comment: %s", databaseName, err)
+	}
+	return readComment(db, datid, "pg_database")
+}
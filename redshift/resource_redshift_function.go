@@ -0,0 +1,280 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_FUNCTION.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_EXTERNAL_FUNCTION.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_DROP_FUNCTION.html
+
+/*
+Scalar SQL and Python UDFs take a body, Lambda UDFs (language lambda) take lambda_name and iam_role instead.
+Like redshift_procedure, the id is the oid, and the schema, name, arguments and return type can't be changed in place.
+SQL and Lambda UDFs can't name their arguments, SQL UDFs refer to them as $1, $2...
+The return type is read back and compared with the config once normalized like the argument types, eg int and integer.
+The lambda and iam role of a Lambda UDF aren't in the catalogs, so lambda_name and iam_role are write only: changing
+them outside terraform isn't detected. Its owner, volatility, arguments and return type are.
+*/
+func redshiftFunction() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftFunctionCreate,
+		Read:   resourceRedshiftFunctionRead,
+		Update: resourceRedshiftFunctionUpdate,
+		Delete: resourceRedshiftFunctionDelete,
+		Exists: resourceRedshiftFunctionExists,
+
+		Schema: map[string]*schema.Schema{
+			"schema": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"arguments": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     routineArgumentSchema(false),
+			},
+			"return_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"language": { //Lowercase, as the catalog reports it
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"sql", "plpythonu", "lambda"}, false),
+			},
+			"volatility": { //Lambda UDFs can't be IMMUTABLE
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "VOLATILE",
+				ValidateFunc: validation.StringInSlice([]string{"VOLATILE", "STABLE", "IMMUTABLE"}, true),
+				StateFunc:    upperCaseStateFunc,
+			},
+			"body": { //sql and plpythonu only, without the $$ quotes
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressSurroundingWhitespaceDiff,
+				ConflictsWith:    []string{"lambda_name"},
+			},
+			"lambda_name": { //lambda only
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"body"},
+			},
+			"iam_role": { //lambda only, arn of the role or default
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"owner": { //usesysid, defaults to user specified in provider
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"signature": { //schema.name(argument types), as used to alter or drop the function
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceRedshiftFunctionExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	// Exists - This is called to verify a resource still exists. It is called prior to Read,
+	// and lowers the burden of Read to be able to assume the resource exists.
+	client, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return false, connErr
+	}
+
+	return routineExists(client, d.Id())
+}
+
+func resourceRedshiftFunctionCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	if err := createOrReplaceFunction(tx, d); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	oid, err := findRoutineOid(tx, false, d.Get("schema").(string), d.Get("name").(string), d.Get("arguments").([]interface{}))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	d.SetId(oid)
+
+	if v, ok := d.GetOk("owner"); ok {
+		if err := alterRoutineOwner(tx, "FUNCTION", d.Id(), v.(int)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := readRedshiftFunction(d, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func resourceRedshiftFunctionRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	return readRedshiftFunction(d, redshiftClient)
+}
+
+func readRedshiftFunction(d *schema.ResourceData, q Queryer) error {
+
+	routine, err := readRoutine(q, d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("schema", routine.schemaName)
+	d.Set("name", routine.name)
+	d.Set("volatility", routine.volatility)
+	d.Set("owner", routine.owner)
+	d.Set("signature", routine.signature)
+	readRoutineArguments(d, routine)
+
+	//Replaces the function if the return type changed outside terraform
+	if normalizeArgumentType(d.Get("return_type").(string)) != routine.returnType {
+		log.Printf("Return type of %s is %s rather than %s", routine.signature, routine.returnType, d.Get("return_type").(string))
+		d.Set("return_type", routine.returnType)
+	}
+
+	if d.Get("language").(string) != "lambda" {
+		d.Set("language", routine.language)
+		d.Set("body", routine.body)
+	}
+
+	return nil
+}
+
+func resourceRedshiftFunctionUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	if d.HasChange("body") || d.HasChange("volatility") || d.HasChange("lambda_name") || d.HasChange("iam_role") {
+		if err := createOrReplaceFunction(tx, d); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if d.HasChange("owner") {
+		if err := alterRoutineOwner(tx, "FUNCTION", d.Id(), d.Get("owner").(int)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := readRedshiftFunction(d, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func resourceRedshiftFunctionDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	return dropRoutine(redshiftClient, "FUNCTION", d.Id())
+}
+
+func createOrReplaceFunction(tx *sql.Tx, d *schema.ResourceData) error {
+
+	var (
+		language        = d.Get("language").(string)
+		createStatement string
+	)
+
+	if language == "lambda" {
+		lambdaName, lambdaOk := d.GetOk("lambda_name")
+		iamRole, iamRoleOk := d.GetOk("iam_role")
+		if !lambdaOk || !iamRoleOk {
+			return NewError("lambda_name and iam_role have to be set for lambda functions")
+		}
+
+		if strings.ToLower(iamRole.(string)) != "default" {
+			iamRole = quoteLiteral(iamRole.(string))
+		}
+
+		createStatement = "CREATE OR REPLACE EXTERNAL FUNCTION " + qualifiedName(d) +
+			"(" + routineArgumentList(d.Get("arguments").([]interface{}), false) + ")" +
+			" RETURNS " + d.Get("return_type").(string) +
+			" " + strings.ToUpper(d.Get("volatility").(string)) +
+			" LAMBDA " + quoteLiteral(lambdaName.(string)) +
+			" IAM_ROLE " + iamRole.(string)
+	} else {
+		body, ok := d.GetOk("body")
+		if !ok {
+			return fmt.Errorf("body has to be set for %s functions", language)
+		}
+
+		createStatement = "CREATE OR REPLACE FUNCTION " + qualifiedName(d) +
+			"(" + routineArgumentList(d.Get("arguments").([]interface{}), false) + ")" +
+			" RETURNS " + d.Get("return_type").(string) +
+			" " + strings.ToUpper(d.Get("volatility").(string)) +
+			" AS " + dollarQuote(body.(string)) +
+			" LANGUAGE " + language
+	}
+
+	log.Print("Create function statement: " + createStatement)
+
+	if _, err := tx.Exec(createStatement); err != nil {
+		return fmt.Errorf("Could not create function %s: %s", qualifiedName(d), err)
+	}
+	return nil
+}
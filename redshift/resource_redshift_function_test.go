@@ -0,0 +1,117 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestCreateOrReplaceFunction(t *testing.T) {
+	cases := []struct {
+		name     string
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			"sql",
+			map[string]interface{}{
+				"schema": "etl", "name": "f_add", "language": "sql", "return_type": "integer", "volatility": "stable",
+				"arguments": []interface{}{map[string]interface{}{"type": "integer"}, map[string]interface{}{"type": "integer"}},
+				"body":      "select $1 + $2",
+			},
+			`CREATE OR REPLACE FUNCTION "etl"."f_add"(integer, integer) RETURNS integer STABLE AS $$select $1 + $2$$ LANGUAGE sql`,
+		},
+		{
+			"lambda",
+			map[string]interface{}{
+				"schema": "etl", "name": "f_enrich", "language": "lambda", "return_type": "varchar",
+				"arguments":   []interface{}{map[string]interface{}{"type": "varchar"}},
+				"lambda_name": "enrich", "iam_role": "arn:aws:iam::123456789012:role/lambda",
+			},
+			`CREATE OR REPLACE EXTERNAL FUNCTION "etl"."f_enrich"(varchar) RETURNS varchar VOLATILE LAMBDA 'enrich' IAM_ROLE 'arn:aws:iam::123456789012:role/lambda'`,
+		},
+	}
+
+	for _, c := range cases {
+		var db, statements = newRecordingFakeDb()
+		var d = schema.TestResourceDataRaw(t, redshiftFunction().Schema, c.raw)
+
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := createOrReplaceFunction(tx, d); err != nil {
+			t.Errorf("%s: err: %s", c.name, err)
+		}
+		if expected := []string{c.expected}; !reflect.DeepEqual(*statements, expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, expected, *statements)
+		}
+		tx.Rollback()
+		db.Close()
+	}
+}
+
+func TestCreateOrReplaceLambdaFunctionWithoutRole(t *testing.T) {
+	var db, statements = newRecordingFakeDb()
+	defer db.Close()
+
+	var d = schema.TestResourceDataRaw(t, redshiftFunction().Schema, map[string]interface{}{
+		"schema": "etl", "name": "f_enrich", "language": "lambda", "return_type": "varchar", "lambda_name": "enrich",
+	})
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer tx.Rollback()
+
+	if err := createOrReplaceFunction(tx, d); err == nil {
+		t.Errorf("expected an error without iam_role")
+	}
+	if len(*statements) > 0 {
+		t.Errorf("expected no statements, got %v", *statements)
+	}
+}
+
+func TestReadRedshiftFunction(t *testing.T) {
+	cases := []struct {
+		name               string
+		language           string
+		returnType         string
+		expectedBody       string
+		expectedReturnType string
+	}{
+		{"body changed outside terraform", "sql", "integer", "select $1 * 2", "int"},
+		{"lambda functions have no body", "lambda", "integer", "", "int"},
+		{"return type changed outside terraform", "sql", "bigint", "select $1 * 2", "bigint"},
+	}
+
+	for _, c := range cases {
+		var db = newFakeDb(fakeResult{match: "FROM pg_proc_info", rows: [][]driver.Value{
+			{"etl", "f_double", c.language, "select $1 * 2", int64(100), false, "i", `"etl"."f_double"(integer)`, "integer", c.returnType},
+		}})
+		var d = schema.TestResourceDataRaw(t, redshiftFunction().Schema, map[string]interface{}{
+			"schema": "etl", "name": "f_double", "language": c.language, "return_type": "int", "body": "",
+		})
+		d.SetId("1000")
+
+		if err := readRedshiftFunction(d, db); err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if body := d.Get("body").(string); body != c.expectedBody {
+			t.Errorf("%s: expected body %q, got %q", c.name, c.expectedBody, body)
+		}
+		if returnType := d.Get("return_type").(string); returnType != c.expectedReturnType {
+			t.Errorf("%s: expected return type %s, got %s", c.name, c.expectedReturnType, returnType)
+		}
+		if volatility := d.Get("volatility").(string); volatility != "IMMUTABLE" {
+			t.Errorf("%s: expected IMMUTABLE, got %s", c.name, volatility)
+		}
+		if owner := d.Get("owner").(int); owner != 100 {
+			t.Errorf("%s: expected owner 100, got %d", c.name, owner)
+		}
+		db.Close()
+	}
+}
@@ -31,6 +31,7 @@ func redshiftGroup() *schema.Resource {
 				Required: true,
 			},
 			//Pass usesysid as username can change
+			//Authoritative, don't combine with redshift_group_membership for the same group
 			"users": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -67,8 +68,14 @@ func resourceRedshiftGroupCreate(d *schema.ResourceData, meta interface{}) error
 
 	var createStatement string = "create group " + d.Get("group_name").(string)
 	if v, ok := d.GetOk("users"); ok {
-		var usernames = GetUsersnamesForUsesysid(tx, v.(*schema.Set).List())
-		createStatement += " WITH USER " + strings.Join(usernames, ", ")
+		usernames, err := GetUsersnamesForUsesysid(tx, v.(*schema.Set).List())
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if len(usernames) > 0 {
+			createStatement += " WITH USER " + strings.Join(usernames, ", ")
+		}
 	}
 
 	log.Print("Create group statement: " + createStatement)
@@ -141,24 +148,46 @@ func readRedshiftGroup(d *schema.ResourceData, tx *sql.Tx) error {
 
 	//Notes on postgres array types https://gist.github.com/adharris/4163702, eg startying with underscore _int4
 
-	if users.Valid {
-		var userIdsAsString = strings.Split(users.String[1:len(users.String)-1], ",")
-		var userIdsAsInt = []int{}
+	userIds, err := parseGroupUsers(users)
+	if err != nil {
+		return err
+	}
+
+	d.Set("users", userIds)
 
-		for _, i := range userIdsAsString {
-			j, err := strconv.Atoi(i)
-			if err != nil {
-				panic(err)
-			}
-			userIdsAsInt = append(userIdsAsInt, j)
+	return nil
+}
+
+// grolist is an int array of usesysids, eg {100,101}, or null if the group has no users
+func parseGroupUsers(grolist sql.NullString) ([]int, error) {
+	var userIdsAsInt = []int{}
+
+	if !grolist.Valid || len(grolist.String) <= 2 {
+		return userIdsAsInt, nil
+	}
+
+	var userIdsAsString = strings.Split(grolist.String[1:len(grolist.String)-1], ",")
+
+	for _, i := range userIdsAsString {
+		j, err := strconv.Atoi(i)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse group users %s: %s", grolist.String, err)
 		}
+		userIdsAsInt = append(userIdsAsInt, j)
+	}
+
+	return userIdsAsInt, nil
+}
+
+func GetGroupUsersForGroupId(q Queryer, grosysid int) ([]int, error) {
+
+	var users sql.NullString
 
-		d.Set("users", userIdsAsInt)
-	} else {
-		d.Set("users", []int{})
+	if err := q.QueryRow("SELECT grolist FROM pg_group WHERE grosysid = $1", grosysid).Scan(&users); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return parseGroupUsers(users)
 }
 
 func resourceRedshiftGroupUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -186,18 +215,23 @@ func resourceRedshiftGroupUpdate(d *schema.ResourceData, meta interface{}) error
 		var usersRemoved = difference(oldUserSet.(*schema.Set).List(), newUserSet.(*schema.Set).List())
 		var usersAdded = difference(newUserSet.(*schema.Set).List(), oldUserSet.(*schema.Set).List())
 
-		if len(usersRemoved) > 0 {
-
-			var usersRemovedAsString = GetUsersnamesForUsesysid(tx, usersRemoved)
+		usersRemovedAsString, err := GetUsersnamesForUsesysid(tx, usersRemoved)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		usersAddedAsString, err := GetUsersnamesForUsesysid(tx, usersAdded)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
 
+		if len(usersRemovedAsString) > 0 {
 			if _, err := tx.Exec("ALTER GROUP " + d.Get("group_name").(string) + " DROP USER " + strings.Join(usersRemovedAsString, ", ")); err != nil {
 				return err
 			}
 		}
-		if len(usersAdded) > 0 {
-
-			var usersAddedAsString = GetUsersnamesForUsesysid(tx, usersAdded)
-
+		if len(usersAddedAsString) > 0 {
 			if _, err := tx.Exec("ALTER GROUP " + d.Get("group_name").(string) + " ADD USER " + strings.Join(usersAddedAsString, ", ")); err != nil {
 				return err
 			}
@@ -217,37 +251,32 @@ func resourceRedshiftGroupUpdate(d *schema.ResourceData, meta interface{}) error
 
 func resourceRedshiftGroupDelete(d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(*Client).db
-
-	//We need to drop all privileges and default privileges
-	rows, schemasError := client.Query("select nspname from pg_namespace")
-	defer rows.Close()
-	if schemasError != nil {
-		return schemasError
+	grosysid, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
 	}
 
-	for rows.Next() {
-		var schemaName string
-		err := rows.Scan(&schemaName)
-		if err != nil {
-			//Im not sure how this can happen
-			return err
-		}
-		client.Exec("REVOKE ALL ON ALL TABLES IN SCHEMA " + schemaName + " FROM GROUP " + d.Get("group_name").(string))
-		client.Exec("ALTER DEFAULT PRIVILEGES IN SCHEMA " + schemaName + " REVOKE ALL ON TABLES FROM GROUP " + d.Get("group_name").(string) + " CASCADE")
+	// The name as the acls hold it, which may differ in case from the config
+	groupName, err := GetGroupNameForGroupId(meta.(*Client).db, grosysid)
+	if err != nil {
+		return fmt.Errorf("Could not find redshift group %d: %s", grosysid, err)
 	}
 
-	_, err := client.Exec("DROP GROUP " + d.Get("group_name").(string))
-
-	if err != nil {
-		log.Print(err)
-		return err
+	var plan = func(q Queryer, clusterWide bool) ([]cleanupStatement, error) {
+		return planRevokeGrantedPrivileges(q, granteeTypeGroup, groupName, clusterWide)
 	}
 
-	return nil
+	return dropWithCleanup(meta.(*Client), plan, "DROP GROUP "+quoteIdentifier(groupName))
 }
 
 func resourceRedshiftGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	grosysid, err := resolveImportId(meta.(*Client).db, d.Id(), "group",
+		"SELECT grosysid, groname FROM pg_group WHERE lower(groname) = lower($1)")
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(grosysid)
+
 	if err := resourceRedshiftGroupRead(d, meta); err != nil {
 		return nil, err
 	}
@@ -269,6 +298,50 @@ func GetGroupNameForGroupId(q Queryer, grosysid int) (string, error) {
 	return name, nil
 }
 
+// The groups the user is a member of
+func GetGroupIdsForUsesysid(q Queryer, usesysid int) ([]int, error) {
+
+	rows, err := q.Query("SELECT grosysid, grolist FROM pg_group")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groupIds = []int{}
+	for rows.Next() {
+		var (
+			grosysid int
+			grolist  sql.NullString
+		)
+		if err := rows.Scan(&grosysid, &grolist); err != nil {
+			return nil, err
+		}
+
+		users, err := parseGroupUsers(grolist)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if user == usesysid {
+				groupIds = append(groupIds, grosysid)
+				break
+			}
+		}
+	}
+	return groupIds, rows.Err()
+}
+
+func GetGroupIdForGroupName(q Queryer, groupName string) (int, error) {
+
+	var grosysid int
+
+	err := q.QueryRow("SELECT grosysid FROM pg_group WHERE groname = $1", groupName).Scan(&grosysid)
+	if err != nil {
+		return -1, err
+	}
+	return grosysid, nil
+}
+
 // Complexity: O(n^2)
 // Returns a minus b
 // Inspired by https://github.com/juliangruber/go-intersect/blob/master/intersect.go
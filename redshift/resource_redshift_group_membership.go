@@ -0,0 +1,321 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_GROUP.html
+
+/*
+Unlike redshift_group.users, this is not authoritative: only the listed users are added to and removed from the group,
+other members are left alone. Don't use it with the users of redshift_group for the same group, redshift_group would
+remove the users added here. Several memberships can add users to the same group.
+The id is grosysid:hash, the hash of the users telling memberships of the same group apart. It changes with the users.
+It can be imported with grosysid:usesysid,usesysid for some of the members, or just grosysid for all of them.
+*/
+func redshiftGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftGroupMembershipCreate,
+		Read:   resourceRedshiftGroupMembershipRead,
+		Update: resourceRedshiftGroupMembershipUpdate,
+		Delete: resourceRedshiftGroupMembershipDelete,
+		Exists: resourceRedshiftGroupMembershipExists,
+		Importer: &schema.ResourceImporter{
+			State: resourceRedshiftGroupMembershipImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			//Pass usesysid as username can change
+			"users": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+	}
+}
+
+func resourceRedshiftGroupMembershipExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	// Exists - This is called to verify a resource still exists. It is called prior to Read,
+	// and lowers the burden of Read to be able to assume the resource exists.
+	client := meta.(*Client).db
+
+	var name string
+
+	err := client.QueryRow("SELECT groname FROM pg_group WHERE grosysid = $1", d.Get("group_id").(int)).Scan(&name)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func resourceRedshiftGroupMembershipCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient := meta.(*Client).db
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	var grosysid = d.Get("group_id").(int)
+
+	members, err := GetGroupUsersForGroupId(tx, grosysid)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Could not read members of redshift group %d: %s", grosysid, err)
+	}
+
+	var usersAdded = difference(d.Get("users").(*schema.Set).List(), intsToInterfaces(members))
+
+	if err := alterGroupMembers(tx, grosysid, "ADD", usersAdded); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	d.SetId(groupMembershipId(grosysid, d.Get("users").(*schema.Set).List()))
+
+	if err := readRedshiftGroupMembership(d, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+func resourceRedshiftGroupMembershipRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient := meta.(*Client).db
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	err := readRedshiftGroupMembership(d, tx)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// Only the managed users that are still members are kept, so removing one of them outside terraform shows up as a diff
+func readRedshiftGroupMembership(d *schema.ResourceData, tx *sql.Tx) error {
+
+	var grosysid = d.Get("group_id").(int)
+
+	members, err := GetGroupUsersForGroupId(tx, grosysid)
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	var managedMembers = []int{}
+	for _, user := range d.Get("users").(*schema.Set).List() {
+		if contains(intsToInterfaces(members), user.(int)) {
+			managedMembers = append(managedMembers, user.(int))
+		}
+	}
+
+	d.Set("users", managedMembers)
+
+	return nil
+}
+
+func resourceRedshiftGroupMembershipUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient := meta.(*Client).db
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	var grosysid = d.Get("group_id").(int)
+
+	if d.HasChange("users") {
+
+		oldUserSet, newUserSet := d.GetChange("users")
+
+		members, err := GetGroupUsersForGroupId(tx, grosysid)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Could not read members of redshift group %d: %s", grosysid, err)
+		}
+
+		//Users that have already left the group can't be dropped, users that have already joined can't be added
+		var usersRemoved = difference(oldUserSet.(*schema.Set).List(), newUserSet.(*schema.Set).List())
+		var usersAdded = difference(newUserSet.(*schema.Set).List(), intsToInterfaces(members))
+
+		if err := alterGroupMembers(tx, grosysid, "DROP", intersection(usersRemoved, intsToInterfaces(members))); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := alterGroupMembers(tx, grosysid, "ADD", usersAdded); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		d.SetId(groupMembershipId(grosysid, newUserSet.(*schema.Set).List()))
+	}
+
+	if err := readRedshiftGroupMembership(d, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+func resourceRedshiftGroupMembershipDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient := meta.(*Client).db
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	var grosysid = d.Get("group_id").(int)
+
+	members, err := GetGroupUsersForGroupId(tx, grosysid)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Could not read members of redshift group %d: %s", grosysid, err)
+	}
+
+	var usersRemoved = intersection(d.Get("users").(*schema.Set).List(), intsToInterfaces(members))
+
+	if err := alterGroupMembers(tx, grosysid, "DROP", usersRemoved); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	return nil
+}
+
+// The import id is grosysid:usesysid,usesysid, or just grosysid to take all the current members of the group
+func resourceRedshiftGroupMembershipImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+
+	var parts = strings.SplitN(d.Id(), ":", 2)
+
+	grosysid, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("Could not import group membership %s: expected grosysid or grosysid:usesysid,usesysid", d.Id())
+	}
+
+	var users []interface{}
+	if len(parts) == 2 {
+		for _, user := range strings.Split(parts[1], ",") {
+			usesysid, err := strconv.Atoi(strings.TrimSpace(user))
+			if err != nil {
+				return nil, fmt.Errorf("Could not import group membership %s: expected grosysid or grosysid:usesysid,usesysid", d.Id())
+			}
+			users = append(users, usesysid)
+		}
+	} else {
+		members, err := GetGroupUsersForGroupId(meta.(*Client).db, grosysid)
+		if err != nil {
+			return nil, err
+		}
+		users = intsToInterfaces(members)
+	}
+
+	d.Set("group_id", grosysid)
+	d.Set("users", users)
+	d.SetId(groupMembershipId(grosysid, users))
+
+	if err := resourceRedshiftGroupMembershipRead(d, meta); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// action is ADD or DROP
+func alterGroupMembers(tx *sql.Tx, grosysid int, action string, users []interface{}) error {
+
+	if len(users) == 0 {
+		return nil
+	}
+
+	groupName, err := GetGroupNameForGroupId(tx, grosysid)
+	if err != nil {
+		return fmt.Errorf("Could not find redshift group %d: %s", grosysid, err)
+	}
+
+	usernames, err := GetUsersnamesForUsesysid(tx, users)
+	if err != nil {
+		return err
+	}
+
+	//Users that have been dropped are no longer members anyway
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	var alterGroupStatement = "ALTER GROUP " + groupName + " " + action + " USER " + strings.Join(usernames, ", ")
+
+	log.Print("Alter group statement: " + alterGroupStatement)
+
+	if _, err := tx.Exec(alterGroupStatement); err != nil {
+		return err
+	}
+	return nil
+}
+
+func groupMembershipId(grosysid int, users []interface{}) string {
+
+	var usesysids []string
+	for _, user := range users {
+		usesysids = append(usesysids, strconv.Itoa(user.(int)))
+	}
+	sort.Strings(usesysids)
+
+	return strconv.Itoa(grosysid) + ":" + strconv.Itoa(hashcode.String(strings.Join(usesysids, ",")))
+}
+
+// Returns the elements of a that are also in b
+func intersection(a []interface{}, b []interface{}) []interface{} {
+
+	set := make([]interface{}, 0)
+
+	for i := 0; i < len(a); i++ {
+		el := a[i].(int)
+		if contains(b, el) {
+			set = append(set, el)
+		}
+	}
+
+	return set
+}
+
+func intsToInterfaces(ints []int) []interface{} {
+	var interfaces = make([]interface{}, 0, len(ints))
+	for _, i := range ints {
+		interfaces = append(interfaces, i)
+	}
+	return interfaces
+}
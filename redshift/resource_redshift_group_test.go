@@ -0,0 +1,47 @@
+package redshift
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestParseGroupUsers(t *testing.T) {
+	cases := []struct {
+		grolist  sql.NullString
+		expected []int
+	}{
+		{sql.NullString{}, []int{}},
+		{sql.NullString{String: "{}", Valid: true}, []int{}},
+		{sql.NullString{String: "{100}", Valid: true}, []int{100}},
+		{sql.NullString{String: "{100,101,1}", Valid: true}, []int{100, 101, 1}},
+	}
+
+	for _, c := range cases {
+		users, err := parseGroupUsers(c.grolist)
+		if err != nil {
+			t.Fatalf("parseGroupUsers(%v): err: %s", c.grolist, err)
+		}
+		if !reflect.DeepEqual(users, c.expected) {
+			t.Errorf("parseGroupUsers(%v): expected %v, got %v", c.grolist, c.expected, users)
+		}
+	}
+
+	if _, err := parseGroupUsers(sql.NullString{String: "{100,abc}", Valid: true}); err == nil {
+		t.Error("expected an error for a grolist that isn't an int array")
+	}
+}
+
+func TestGroupMembershipId(t *testing.T) {
+	var id = groupMembershipId(100, []interface{}{101, 102})
+
+	if other := groupMembershipId(100, []interface{}{102, 101}); other != id {
+		t.Errorf("expected the id not to depend on the order of users, got %s and %s", id, other)
+	}
+	if other := groupMembershipId(100, []interface{}{101}); other == id {
+		t.Errorf("expected memberships with different users to have different ids, got %s", id)
+	}
+	if other := groupMembershipId(200, []interface{}{101, 102}); other == id {
+		t.Errorf("expected memberships of different groups to have different ids, got %s", id)
+	}
+}
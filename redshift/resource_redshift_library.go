@@ -0,0 +1,203 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_LIBRARY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_DROP_LIBRARY.html
+
+/*
+The id is the name of the library, pg_library has no id column. Only the name and owner are in pg_library, so the
+location and credentials can't be checked for drift. Changing the location or region installs the library again
+with CREATE OR REPLACE LIBRARY.
+*/
+func redshiftLibrary() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftLibraryCreate,
+		Read:   resourceRedshiftLibraryRead,
+		Update: resourceRedshiftLibraryUpdate,
+		Delete: resourceRedshiftLibraryDelete,
+		Exists: resourceRedshiftLibraryExists,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"language": { //Redshift only supports plpythonu
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "plpythonu",
+				ValidateFunc: validation.StringInSlice([]string{"plpythonu"}, false),
+			},
+			"location": { //s3://bucket/file.zip or https://...
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"iam_role": { //arn of a role that can read the s3 location
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"credentials"},
+			},
+			"credentials": { //aws-auth-args, eg aws_access_key_id=...;aws_secret_access_key=...
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"iam_role"},
+			},
+			"region": { //Only needed if the bucket isn't in the region of the cluster
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"owner": { //usesysid of the user that installed the library
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceRedshiftLibraryExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	// Exists - This is called to verify a resource still exists. It is called prior to Read,
+	// and lowers the burden of Read to be able to assume the resource exists.
+	client, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return false, connErr
+	}
+
+	var name string
+
+	err := client.QueryRow("SELECT name FROM pg_library WHERE name = $1", d.Id()).Scan(&name)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func resourceRedshiftLibraryCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	if err := createOrReplaceLibrary(redshiftClient, d, false); err != nil {
+		return err
+	}
+
+	d.SetId(d.Get("name").(string))
+
+	return readRedshiftLibrary(d, redshiftClient)
+}
+
+func resourceRedshiftLibraryRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	return readRedshiftLibrary(d, redshiftClient)
+}
+
+func readRedshiftLibrary(d *schema.ResourceData, db *sql.DB) error {
+
+	var owner int
+
+	if err := db.QueryRow("SELECT owner FROM pg_library WHERE name = $1", d.Id()).Scan(&owner); err != nil {
+		log.Print(err)
+		return err
+	}
+
+	d.Set("name", d.Id())
+	d.Set("owner", owner)
+
+	return nil
+}
+
+func resourceRedshiftLibraryUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	//New credentials are only needed the next time the library is installed
+	if d.HasChange("location") || d.HasChange("region") || d.HasChange("language") {
+		if err := createOrReplaceLibrary(redshiftClient, d, true); err != nil {
+			return err
+		}
+	}
+
+	return readRedshiftLibrary(d, redshiftClient)
+}
+
+func resourceRedshiftLibraryDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	if _, err := redshiftClient.Exec("DROP LIBRARY " + quoteIdentifier(d.Id())); err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
+
+// CREATE LIBRARY can't run inside a transaction block
+func createOrReplaceLibrary(db *sql.DB, d *schema.ResourceData, replace bool) error {
+
+	var (
+		location        = d.Get("location").(string)
+		createStatement = "CREATE LIBRARY "
+	)
+
+	if replace {
+		createStatement = "CREATE OR REPLACE LIBRARY "
+	}
+
+	createStatement += quoteIdentifier(d.Get("name").(string)) +
+		" LANGUAGE " + d.Get("language").(string) +
+		" FROM " + quoteLiteral(location)
+
+	//Only the statement without the authorization is logged, so credentials stay out of the logs
+	var authorization string
+	if v, ok := d.GetOk("iam_role"); ok {
+		authorization = " IAM_ROLE " + quoteLiteral(v.(string))
+	} else if v, ok := d.GetOk("credentials"); ok {
+		authorization = " CREDENTIALS " + quoteLiteral(v.(string))
+	} else if strings.HasPrefix(strings.ToLower(location), "s3://") {
+		return NewError("iam_role or credentials have to be set to install a library from s3")
+	}
+
+	if v, ok := d.GetOk("region"); ok {
+		authorization += " REGION " + quoteLiteral(v.(string))
+	}
+
+	log.Print("Create library statement: " + createStatement)
+
+	if _, err := db.Exec(createStatement + authorization); err != nil {
+		return fmt.Errorf("Could not create library %s: %s", d.Get("name").(string), err)
+	}
+	return nil
+}
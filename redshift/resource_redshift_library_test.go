@@ -0,0 +1,60 @@
+package redshift
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestCreateOrReplaceLibrary(t *testing.T) {
+	cases := []struct {
+		name     string
+		raw      map[string]interface{}
+		replace  bool
+		expected []string
+	}{
+		{
+			"iam role",
+			map[string]interface{}{"location": "s3://bucket/lib.zip", "iam_role": "arn:aws:iam::123456789012:role/s3", "region": "us-east-1"},
+			false,
+			[]string{`CREATE LIBRARY "f_lib" LANGUAGE plpythonu FROM 's3://bucket/lib.zip' IAM_ROLE 'arn:aws:iam::123456789012:role/s3' REGION 'us-east-1'`},
+		},
+		{
+			"credentials",
+			map[string]interface{}{"location": "s3://bucket/lib.zip", "credentials": "aws_access_key_id=a;aws_secret_access_key=b"},
+			true,
+			[]string{`CREATE OR REPLACE LIBRARY "f_lib" LANGUAGE plpythonu FROM 's3://bucket/lib.zip' CREDENTIALS 'aws_access_key_id=a;aws_secret_access_key=b'`},
+		},
+		{
+			"https",
+			map[string]interface{}{"location": "https://example.com/lib.zip"},
+			false,
+			[]string{`CREATE LIBRARY "f_lib" LANGUAGE plpythonu FROM 'https://example.com/lib.zip'`},
+		},
+		{
+			"s3 without authorization",
+			map[string]interface{}{"location": "s3://bucket/lib.zip"},
+			false,
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		var db, statements = newRecordingFakeDb()
+
+		c.raw["name"] = "f_lib"
+		var d = schema.TestResourceDataRaw(t, redshiftLibrary().Schema, c.raw)
+
+		var err = createOrReplaceLibrary(db, d, c.replace)
+		if len(c.expected) == 0 && err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		} else if len(c.expected) > 0 && err != nil {
+			t.Errorf("%s: err: %s", c.name, err)
+		}
+		if !reflect.DeepEqual(*statements, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, *statements)
+		}
+		db.Close()
+	}
+}
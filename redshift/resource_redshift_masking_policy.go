@@ -0,0 +1,183 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_MASKING_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_MASKING_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_DROP_MASKING_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_SVV_MASKING_POLICY.html
+
+/*
+The id is the name of the policy. Policies are attached to table columns with redshift_masking_policy_attachment.
+Like redshift_rls_policy, a hash of the expression Redshift stores is kept after every apply, and an expression
+changed outside terraform is put in using so the next plan sets it back.
+The input columns can't be altered, changing them recreates the policy, which has to be detached from every table first.
+*/
+func redshiftMaskingPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftMaskingPolicyCreate,
+		Read:   resourceRedshiftMaskingPolicyRead,
+		Update: resourceRedshiftMaskingPolicyUpdate,
+		Delete: resourceRedshiftMaskingPolicyDelete,
+		Exists: resourceRedshiftMaskingPolicyExists,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"with_columns": { //The input columns using refers to
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				Elem:     routineArgumentSchema(false),
+			},
+			"using": { //The masking expressions, one per output column, eg SHA2(credit_card + 'salt', 256)
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressSurroundingWhitespaceDiff,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"using_hash": { //Hash of the expression in svv_masking_policy at the last apply
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceRedshiftMaskingPolicyExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	// Exists - This is called to verify a resource still exists. It is called prior to Read,
+	// and lowers the burden of Read to be able to assume the resource exists.
+	client, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return false, connErr
+	}
+
+	_, err := readMaskingPolicyExpression(client, d.Id())
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func resourceRedshiftMaskingPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	var createStatement = "CREATE MASKING POLICY " + quoteIdentifier(d.Get("name").(string)) +
+		" WITH (" + routineArgumentList(d.Get("with_columns").([]interface{}), false) + ")" +
+		" USING (" + strings.TrimSpace(d.Get("using").(string)) + ")"
+
+	log.Print("Create masking policy statement: " + createStatement)
+
+	if _, err := redshiftClient.Exec(createStatement); err != nil {
+		return fmt.Errorf("Could not create masking policy %s: %s", d.Get("name").(string), err)
+	}
+
+	d.SetId(d.Get("name").(string))
+
+	return readRedshiftMaskingPolicyAfterApply(d, redshiftClient)
+}
+
+func resourceRedshiftMaskingPolicyRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	expression, err := readMaskingPolicyExpression(redshiftClient, d.Id())
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	d.Set("name", d.Id())
+
+	if hash := sha256Hex(expression); d.Get("using_hash").(string) != "" && hash != d.Get("using_hash").(string) {
+		log.Printf("Expression of masking policy %s has changed outside terraform", d.Id())
+		d.Set("using", expression)
+	}
+
+	return nil
+}
+
+func readRedshiftMaskingPolicyAfterApply(d *schema.ResourceData, q Queryer) error {
+
+	expression, err := readMaskingPolicyExpression(q, d.Id())
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	d.Set("name", d.Id())
+	d.Set("using_hash", sha256Hex(expression))
+
+	return nil
+}
+
+func resourceRedshiftMaskingPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	if d.HasChange("using") {
+		var alterStatement = "ALTER MASKING POLICY " + quoteIdentifier(d.Id()) + " USING (" + strings.TrimSpace(d.Get("using").(string)) + ")"
+
+		log.Print("Alter masking policy statement: " + alterStatement)
+
+		if _, err := redshiftClient.Exec(alterStatement); err != nil {
+			return fmt.Errorf("Could not alter masking policy %s: %s", d.Id(), err)
+		}
+	}
+
+	return readRedshiftMaskingPolicyAfterApply(d, redshiftClient)
+}
+
+func resourceRedshiftMaskingPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	//Fails while the policy is still attached, attachments that depend on the policy are destroyed first
+	if _, err := redshiftClient.Exec("DROP MASKING POLICY " + quoteIdentifier(d.Id())); err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
+
+func readMaskingPolicyExpression(q Queryer, name string) (string, error) {
+
+	var expression sql.NullString
+
+	err := q.QueryRow(`SELECT policy_expression FROM svv_masking_policy
+		WHERE policy_database = current_database() AND policy_name = $1`, name).Scan(&expression)
+
+	return expression.String, err
+}
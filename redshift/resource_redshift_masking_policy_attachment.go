@@ -0,0 +1,183 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_ATTACH_MASKING_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_DETACH_MASKING_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_SVV_ATTACHED_MASKING_POLICY.html
+
+/*
+Attaches a masking policy to columns of one table for one user or role, like redshift_rls_policy_attachment.
+An attachment can't be altered, so any change, including a priority changed outside terraform, detaches and attaches again.
+The id is policy:schema.table:user:usesysid or policy:schema.table:role:role_id.
+*/
+func redshiftMaskingPolicyAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftMaskingPolicyAttachmentCreate,
+		Read:   resourceRedshiftMaskingPolicyAttachmentRead,
+		Delete: resourceRedshiftMaskingPolicyAttachmentDelete,
+
+		Schema: map[string]*schema.Schema{
+			"policy_name": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"schema": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"table": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"output_columns": { //The columns that are masked
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString, ValidateFunc: validateLowercaseName},
+			},
+			"input_columns": { //The columns passed to the policy, defaults to the output columns
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString, ValidateFunc: validateLowercaseName},
+			},
+			//Exactly one of user_id and role_id has to be set
+			"user_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"role_id"},
+			},
+			"role_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"user_id"},
+			},
+			"priority": { //The policy with the highest priority applies if several mask the same column for a user
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+				Default:  0,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceRedshiftMaskingPolicyAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	grantee, err := resolvePolicyGrantee(redshiftClient, d)
+	if err != nil {
+		return err
+	}
+
+	var attachStatement = "ATTACH MASKING POLICY " + quoteIdentifier(d.Get("policy_name").(string)) +
+		" ON " + qualifiedTableName(d) + " (" + quotedColumnList(d.Get("output_columns").([]interface{})) + ")"
+
+	if v, ok := d.GetOk("input_columns"); ok {
+		attachStatement += " USING (" + quotedColumnList(v.([]interface{})) + ")"
+	}
+
+	attachStatement += " TO " + grantee.clause() + " PRIORITY " + strconv.Itoa(d.Get("priority").(int))
+
+	log.Print("Attach masking policy statement: " + attachStatement)
+
+	if _, err := redshiftClient.Exec(attachStatement); err != nil {
+		return fmt.Errorf("Could not attach masking policy %s: %s", d.Get("policy_name").(string), err)
+	}
+
+	d.SetId(d.Get("policy_name").(string) + ":" + d.Get("schema").(string) + "." + d.Get("table").(string) + ":" + grantee.kind + ":" + strconv.Itoa(grantee.id))
+
+	return resourceRedshiftMaskingPolicyAttachmentRead(d, meta)
+}
+
+func resourceRedshiftMaskingPolicyAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	grantee, err := resolvePolicyGrantee(redshiftClient, d)
+	if err == sql.ErrNoRows {
+		//The user or role has been dropped, which detaches the policy
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var priority int
+
+	err = redshiftClient.QueryRow(`SELECT priority FROM svv_attached_masking_policy
+		WHERE policy_name = $1 AND schema_name = $2 AND table_name = $3 AND grantee = $4 AND grantee_type = $5`,
+		d.Get("policy_name").(string), d.Get("schema").(string), d.Get("table").(string), grantee.name, grantee.kind).Scan(&priority)
+	if err == sql.ErrNoRows {
+		log.Printf("Masking policy attachment %s no longer exists", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	d.Set("priority", priority)
+
+	return nil
+}
+
+func resourceRedshiftMaskingPolicyAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	grantee, err := resolvePolicyGrantee(redshiftClient, d)
+	if err == sql.ErrNoRows {
+		//The user or role has been dropped, which already detached the policy
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var detachStatement = "DETACH MASKING POLICY " + quoteIdentifier(d.Get("policy_name").(string)) +
+		" ON " + qualifiedTableName(d) + " (" + quotedColumnList(d.Get("output_columns").([]interface{})) + ")" +
+		" FROM " + grantee.clause()
+
+	log.Print("Detach masking policy statement: " + detachStatement)
+
+	if _, err := redshiftClient.Exec(detachStatement); err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
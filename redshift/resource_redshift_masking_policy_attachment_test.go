@@ -0,0 +1,61 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestQuotedColumnList(t *testing.T) {
+	if quoted := quotedColumnList([]interface{}{"credit_card", `odd"name`}); quoted != `"credit_card", "odd""name"` {
+		t.Errorf(`expected "credit_card", "odd""name", got %s`, quoted)
+	}
+}
+
+func TestResourceRedshiftMaskingPolicyAttachmentCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		raw      map[string]interface{}
+		expected string
+		id       string
+	}{
+		{
+			"user",
+			map[string]interface{}{"user_id": 100},
+			`ATTACH MASKING POLICY "mask_credit_card" ON "sales"."customers" ("credit_card") TO "etl" PRIORITY 0`,
+			"mask_credit_card:sales.customers:user:100",
+		},
+		{
+			"role with input columns",
+			map[string]interface{}{"role_id": 200, "input_columns": []interface{}{"card_number"}, "priority": 10},
+			`ATTACH MASKING POLICY "mask_credit_card" ON "sales"."customers" ("credit_card") USING ("card_number") TO ROLE "analyst" PRIORITY 10`,
+			"mask_credit_card:sales.customers:role:200",
+		},
+	}
+
+	for _, c := range cases {
+		var db, statements = newRecordingFakeDb(
+			fakeResult{match: "FROM pg_user_info", rows: [][]driver.Value{{"etl"}}},
+			fakeResult{match: "FROM svv_roles", rows: [][]driver.Value{{"analyst"}}},
+			fakeResult{match: "FROM svv_attached_masking_policy", rows: [][]driver.Value{{int64(10)}}},
+		)
+		c.raw["policy_name"] = "mask_credit_card"
+		c.raw["schema"] = "sales"
+		c.raw["table"] = "customers"
+		c.raw["output_columns"] = []interface{}{"credit_card"}
+		var d = schema.TestResourceDataRaw(t, redshiftMaskingPolicyAttachment().Schema, c.raw)
+
+		if err := resourceRedshiftMaskingPolicyAttachmentCreate(d, &Client{db: db}); err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if !reflect.DeepEqual(*statements, []string{c.expected}) {
+			t.Errorf("%s: expected %s, got %v", c.name, c.expected, *statements)
+		}
+		if d.Id() != c.id {
+			t.Errorf("%s: expected id %s, got %s", c.name, c.id, d.Id())
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,44 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceRedshiftMaskingPolicyReadUsingDrift(t *testing.T) {
+	const (
+		configured = "SHA2(credit_card + 'salt', 256)"
+		stored     = "sha2(((credit_card)::text + 'salt'::text), 256)"
+	)
+
+	cases := []struct {
+		name      string
+		usingHash string
+		expected  string
+	}{
+		{"unchanged", sha256Hex(stored), configured},
+		{"changed outside terraform", sha256Hex("NULL"), stored},
+		{"no hash yet", "", configured},
+	}
+
+	for _, c := range cases {
+		var db = newFakeDb(fakeResult{match: "FROM svv_masking_policy", rows: [][]driver.Value{{stored}}})
+		var d = schema.TestResourceDataRaw(t, redshiftMaskingPolicy().Schema, map[string]interface{}{
+			"name":         "mask_credit_card",
+			"with_columns": []interface{}{map[string]interface{}{"name": "credit_card", "type": "varchar(256)"}},
+			"using":        configured,
+		})
+		d.SetId("mask_credit_card")
+		d.Set("using_hash", c.usingHash)
+
+		if err := resourceRedshiftMaskingPolicyRead(d, &Client{db: db}); err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if using := d.Get("using").(string); using != c.expected {
+			t.Errorf("%s: expected using %q, got %q", c.name, c.expected, using)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,503 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_PROCEDURE.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_PROCEDURE.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_DROP_PROCEDURE.html
+
+/*
+The id is the oid of the procedure, which CREATE OR REPLACE keeps, so changing the body or security is an update.
+Changing the schema, name or arguments is a different procedure, so those recreate it.
+The input argument types are read back, so a procedure whose types no longer match the config is recreated.
+*/
+func redshiftProcedure() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftProcedureCreate,
+		Read:   resourceRedshiftProcedureRead,
+		Update: resourceRedshiftProcedureUpdate,
+		Delete: resourceRedshiftProcedureDelete,
+		Exists: resourceRedshiftProcedureExists,
+
+		Schema: map[string]*schema.Schema{
+			"schema": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"arguments": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     routineArgumentSchema(true),
+			},
+			"language": { //Redshift only supports plpgsql for procedures. Lowercase, as the catalog reports it
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "plpgsql",
+				ValidateFunc: validation.StringInSlice([]string{"plpgsql"}, false),
+			},
+			"body": { //Without the $$ quotes
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressSurroundingWhitespaceDiff,
+			},
+			"security": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "INVOKER",
+				ValidateFunc: validation.StringInSlice([]string{"INVOKER", "DEFINER"}, true),
+				StateFunc:    upperCaseStateFunc,
+			},
+			"owner": { //usesysid, defaults to user specified in provider
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"signature": { //schema.name(argument types), as used to alter or drop the procedure
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func routineArgumentSchema(withMode bool) *schema.Resource {
+	var argumentSchema = map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"type": { //eg varchar(256), integer
+			Type:     schema.TypeString,
+			Required: true,
+		},
+	}
+	if withMode {
+		argumentSchema["mode"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "IN",
+			ValidateFunc: validation.StringInSlice([]string{"IN", "OUT", "INOUT"}, true),
+		}
+	}
+	return &schema.Resource{Schema: argumentSchema}
+}
+
+func resourceRedshiftProcedureExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	// Exists - This is called to verify a resource still exists. It is called prior to Read,
+	// and lowers the burden of Read to be able to assume the resource exists.
+	client, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return false, connErr
+	}
+
+	return routineExists(client, d.Id())
+}
+
+func resourceRedshiftProcedureCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	if err := createOrReplaceProcedure(tx, d); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	oid, err := findRoutineOid(tx, true, d.Get("schema").(string), d.Get("name").(string), d.Get("arguments").([]interface{}))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	d.SetId(oid)
+
+	if v, ok := d.GetOk("owner"); ok {
+		if err := alterRoutineOwner(tx, "PROCEDURE", d.Id(), v.(int)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := readRedshiftProcedure(d, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func resourceRedshiftProcedureRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	return readRedshiftProcedure(d, redshiftClient)
+}
+
+func readRedshiftProcedure(d *schema.ResourceData, q Queryer) error {
+
+	routine, err := readRoutine(q, d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Set("schema", routine.schemaName)
+	d.Set("name", routine.name)
+	d.Set("language", routine.language)
+	d.Set("body", routine.body)
+	d.Set("owner", routine.owner)
+	d.Set("signature", routine.signature)
+	readRoutineArguments(d, routine)
+	if routine.securityDefiner {
+		d.Set("security", "DEFINER")
+	} else {
+		d.Set("security", "INVOKER")
+	}
+
+	return nil
+}
+
+func resourceRedshiftProcedureUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	if d.HasChange("body") || d.HasChange("language") || d.HasChange("security") {
+		if err := createOrReplaceProcedure(tx, d); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if d.HasChange("owner") {
+		if err := alterRoutineOwner(tx, "PROCEDURE", d.Id(), d.Get("owner").(int)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := readRedshiftProcedure(d, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func resourceRedshiftProcedureDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	return dropRoutine(redshiftClient, "PROCEDURE", d.Id())
+}
+
+func createOrReplaceProcedure(tx *sql.Tx, d *schema.ResourceData) error {
+
+	var createStatement = "CREATE OR REPLACE PROCEDURE " + qualifiedName(d) +
+		"(" + routineArgumentList(d.Get("arguments").([]interface{}), true) + ")" +
+		" AS " + dollarQuote(d.Get("body").(string)) +
+		" LANGUAGE " + d.Get("language").(string) +
+		" SECURITY " + strings.ToUpper(d.Get("security").(string))
+
+	log.Print("Create procedure statement: " + createStatement)
+
+	if _, err := tx.Exec(createStatement); err != nil {
+		return fmt.Errorf("Could not create procedure %s: %s", qualifiedName(d), err)
+	}
+	return nil
+}
+
+// Shared by procedures and functions
+
+type routine struct {
+	schemaName      string
+	name            string
+	language        string
+	body            string
+	owner           int
+	securityDefiner bool
+	volatility      string
+	// schema.name(argument types), quoted
+	signature string
+	// The input argument types, as Redshift names them, eg integer, character varying
+	argumentTypes []string
+	// As Redshift names it, eg character varying. void for procedures
+	returnType string
+}
+
+var routineQuery = `SELECT nsp.nspname, p.proname, lang.lanname, COALESCE(p.prosrc, ''), p.proowner, p.prosecdef,
+		p.provolatile,
+		QUOTE_IDENT(nsp.nspname) || '.' || QUOTE_IDENT(p.proname) || '(' || oidvectortypes(p.proargtypes) || ')',
+		oidvectortypes(p.proargtypes),
+		format_type(p.prorettype, NULL)
+	FROM pg_proc_info p
+	JOIN pg_namespace nsp ON nsp.oid = p.pronamespace
+	JOIN pg_language lang ON lang.oid = p.prolang
+	WHERE p.prooid = $1`
+
+var routineVolatilities = map[string]string{
+	"i": "IMMUTABLE",
+	"s": "STABLE",
+	"v": "VOLATILE",
+}
+
+func routineExists(q Queryer, oid string) (bool, error) {
+
+	var name string
+
+	err := q.QueryRow("SELECT proname FROM pg_proc_info WHERE prooid = $1", oid).Scan(&name)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func readRoutine(q Queryer, oid string) (routine, error) {
+
+	var (
+		r             routine
+		volatility    string
+		argumentTypes string
+	)
+
+	err := q.QueryRow(routineQuery, oid).Scan(&r.schemaName, &r.name, &r.language, &r.body, &r.owner,
+		&r.securityDefiner, &volatility, &r.signature, &argumentTypes, &r.returnType)
+	if err != nil {
+		log.Print(err)
+		return r, err
+	}
+
+	r.volatility = routineVolatilities[volatility]
+	if argumentTypes != "" {
+		r.argumentTypes = strings.Split(argumentTypes, ", ")
+	}
+
+	return r, nil
+}
+
+// Redshift doesn't return the oid of a new procedure or function, so it is looked up by name and arguments.
+// Only input arguments are part of proargtypes, and the types are compared by the name Redshift gives them,
+// which is only needed if the name is overloaded with the same number of arguments
+func findRoutineOid(q Queryer, procedure bool, schemaName string, name string, arguments []interface{}) (string, error) {
+
+	var inputTypes = routineInputTypes(arguments)
+
+	rows, err := q.Query(`SELECT p.prooid, oidvectortypes(p.proargtypes)
+		FROM pg_proc_info p
+		JOIN pg_namespace nsp ON nsp.oid = p.pronamespace
+		WHERE nsp.nspname = $1
+		AND   p.proname = $2
+		AND   p.pronargs = $3
+		AND   (p.prokind = 'p') = $4`, schemaName, name, len(inputTypes), procedure)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var oids []string
+	var candidates = map[string]string{}
+	for rows.Next() {
+		var oid, argumentTypes string
+		if err := rows.Scan(&oid, &argumentTypes); err != nil {
+			return "", err
+		}
+		oids = append(oids, oid)
+		candidates[argumentTypes] = oid
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	switch len(oids) {
+	case 0:
+		return "", fmt.Errorf("Could not find %s.%s after creating it", schemaName, name)
+	case 1:
+		return oids[0], nil
+	}
+
+	if oid, ok := candidates[strings.Join(inputTypes, ", ")]; ok {
+		return oid, nil
+	}
+	return "", fmt.Errorf("Could not tell which of the %d overloads of %s.%s was created, use the type names Redshift shows for the arguments", len(oids), schemaName, name)
+}
+
+// The types of the input arguments, normalized to the names Redshift gives them
+func routineInputTypes(arguments []interface{}) []string {
+
+	var inputTypes = []string{}
+	for _, argument := range arguments {
+		var a = argument.(map[string]interface{})
+		if mode, ok := a["mode"]; ok && strings.ToUpper(mode.(string)) == "OUT" {
+			continue
+		}
+		inputTypes = append(inputTypes, normalizeArgumentType(a["type"].(string)))
+	}
+	return inputTypes
+}
+
+// Aliases of the types Redshift lists in proargtypes under another name
+var argumentTypeAliases = map[string]string{
+	"int":         "integer",
+	"int4":        "integer",
+	"int2":        "smallint",
+	"int8":        "bigint",
+	"bool":        "boolean",
+	"float":       "double precision",
+	"float8":      "double precision",
+	"float4":      "real",
+	"decimal":     "numeric",
+	"varchar":     "character varying",
+	"char":        "character",
+	"bpchar":      "character",
+	"nchar":       "character",
+	"nvarchar":    "character varying",
+	"text":        "character varying",
+	"timestamp":   "timestamp without time zone",
+	"timestamptz": "timestamp with time zone",
+	"time":        "time without time zone",
+	"timetz":      "time with time zone",
+}
+
+// Eg VARCHAR(256) becomes character varying, as the length isn't part of the signature
+func normalizeArgumentType(argumentType string) string {
+
+	var normalized = strings.ToLower(strings.TrimSpace(argumentType))
+	if i := strings.Index(normalized, "("); i != -1 {
+		normalized = strings.TrimSpace(normalized[:i])
+	}
+	normalized = strings.Join(strings.Fields(normalized), " ")
+
+	if alias, ok := argumentTypeAliases[normalized]; ok {
+		return alias
+	}
+	return normalized
+}
+
+// The arguments are only read back when their types differ from the config, eg after the routine was replaced
+// outside terraform by one with the same oid, as the names and modes of the arguments aren't in the catalog.
+// Setting them from the catalog then shows the difference and recreates the routine
+func readRoutineArguments(d *schema.ResourceData, r routine) {
+
+	var configured = routineInputTypes(d.Get("arguments").([]interface{}))
+	if strings.Join(configured, ", ") == strings.Join(r.argumentTypes, ", ") {
+		return
+	}
+
+	log.Printf("Arguments of %s are (%s) rather than (%s)", r.signature, strings.Join(r.argumentTypes, ", "), strings.Join(configured, ", "))
+
+	var arguments = []interface{}{}
+	for _, argumentType := range r.argumentTypes {
+		arguments = append(arguments, map[string]interface{}{"type": argumentType})
+	}
+	d.Set("arguments", arguments)
+}
+
+// routineType is PROCEDURE or FUNCTION
+func alterRoutineOwner(tx *sql.Tx, routineType string, oid string, owner int) error {
+
+	r, err := readRoutine(tx, oid)
+	if err != nil {
+		return err
+	}
+
+	username, err := GetUsernameForUsesysid(tx, owner)
+	if err != nil {
+		return fmt.Errorf("Could not find redshift user %d: %s", owner, err)
+	}
+
+	var alterOwnerStatement = "ALTER " + routineType + " " + r.signature + " OWNER TO " + username
+
+	log.Print("Alter owner statement: " + alterOwnerStatement)
+
+	if _, err := tx.Exec(alterOwnerStatement); err != nil {
+		return err
+	}
+	return nil
+}
+
+// The full signature is read from the catalog, so the drop works whatever names the arguments were given as
+func dropRoutine(db *sql.DB, routineType string, oid string) error {
+
+	r, err := readRoutine(db, oid)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var dropStatement = "DROP " + routineType + " " + r.signature
+
+	log.Print("Drop statement: " + dropStatement)
+
+	if _, err := db.Exec(dropStatement); err != nil {
+		log.Print(err)
+		return err
+	}
+	return nil
+}
+
+// Procedures take the name before the mode, eg (id IN integer)
+func routineArgumentList(arguments []interface{}, withMode bool) string {
+
+	var definitions []string
+	for _, argument := range arguments {
+		var (
+			a          = argument.(map[string]interface{})
+			definition []string
+		)
+		if name := a["name"].(string); name != "" {
+			definition = append(definition, name)
+		}
+		if withMode {
+			definition = append(definition, strings.ToUpper(a["mode"].(string)))
+		}
+		definition = append(definition, a["type"].(string))
+		definitions = append(definitions, strings.Join(definition, " "))
+	}
+	return strings.Join(definitions, ", ")
+}
@@ -0,0 +1,36 @@
+package redshift
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestReadRoutineArguments(t *testing.T) {
+	var configured = []interface{}{map[string]interface{}{"name": "id", "type": "int", "mode": "IN"}}
+
+	cases := []struct {
+		name          string
+		argumentTypes []string
+		expected      []interface{}
+	}{
+		{"unchanged", []string{"integer"}, []interface{}{map[string]interface{}{"name": "id", "type": "int", "mode": "IN"}}},
+		{"changed outside terraform", []string{"bigint", "character varying"}, []interface{}{
+			map[string]interface{}{"name": "", "type": "bigint", "mode": ""},
+			map[string]interface{}{"name": "", "type": "character varying", "mode": ""},
+		}},
+	}
+
+	for _, c := range cases {
+		var d = schema.TestResourceDataRaw(t, redshiftProcedure().Schema, map[string]interface{}{
+			"schema": "etl", "name": "p_load", "arguments": configured, "body": "BEGIN END;",
+		})
+
+		readRoutineArguments(d, routine{signature: `"etl"."p_load"`, argumentTypes: c.argumentTypes})
+
+		if arguments := d.Get("arguments").([]interface{}); !reflect.DeepEqual(arguments, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, arguments)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_RLS_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_RLS_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_DROP_RLS_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_SVV_RLS_POLICY.html
+
+/*
+The id is the name of the policy. Policies are attached to tables with redshift_rls_policy_attachment, and only
+filter rows once RLS is turned on for the table with redshift_table_rls.
+Like redshift_view, Redshift rewrites the predicate it stores, so a hash of the stored predicate is kept after every
+apply, and a predicate changed outside terraform is put in using so the next plan sets it back.
+The columns can't be altered, changing them recreates the policy, which detaches it from every table.
+*/
+func redshiftRlsPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftRlsPolicyCreate,
+		Read:   resourceRedshiftRlsPolicyRead,
+		Update: resourceRedshiftRlsPolicyUpdate,
+		Delete: resourceRedshiftRlsPolicyDelete,
+		Exists: resourceRedshiftRlsPolicyExists,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"with_columns": { //Columns of the tables the policy is attached to that using refers to
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     routineArgumentSchema(false),
+			},
+			"table_alias": { //Alias for the table in using
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"using": { //The predicate, eg tenant_id = current_user
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressSurroundingWhitespaceDiff,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"using_hash": { //Hash of the predicate in svv_rls_policy at the last apply
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceRedshiftRlsPolicyExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	// Exists - This is called to verify a resource still exists. It is called prior to Read,
+	// and lowers the burden of Read to be able to assume the resource exists.
+	client, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return false, connErr
+	}
+
+	_, err := readRlsPolicyPredicate(client, d.Id())
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func resourceRedshiftRlsPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	var createStatement = "CREATE RLS POLICY " + quoteIdentifier(d.Get("name").(string))
+
+	if v, ok := d.GetOk("with_columns"); ok {
+		createStatement += " WITH (" + routineArgumentList(v.([]interface{}), false) + ")"
+		if alias, ok := d.GetOk("table_alias"); ok {
+			createStatement += " AS " + alias.(string)
+		}
+	}
+
+	createStatement += " USING (" + strings.TrimSpace(d.Get("using").(string)) + ")"
+
+	log.Print("Create rls policy statement: " + createStatement)
+
+	if _, err := redshiftClient.Exec(createStatement); err != nil {
+		return fmt.Errorf("Could not create rls policy %s: %s", d.Get("name").(string), err)
+	}
+
+	d.SetId(d.Get("name").(string))
+
+	return readRedshiftRlsPolicyAfterApply(d, redshiftClient)
+}
+
+func resourceRedshiftRlsPolicyRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	predicate, err := readRlsPolicyPredicate(redshiftClient, d.Id())
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	d.Set("name", d.Id())
+
+	if hash := sha256Hex(predicate); d.Get("using_hash").(string) != "" && hash != d.Get("using_hash").(string) {
+		log.Printf("Predicate of rls policy %s has changed outside terraform", d.Id())
+		d.Set("using", predicate)
+	}
+
+	return nil
+}
+
+func readRedshiftRlsPolicyAfterApply(d *schema.ResourceData, q Queryer) error {
+
+	predicate, err := readRlsPolicyPredicate(q, d.Id())
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	d.Set("name", d.Id())
+	d.Set("using_hash", sha256Hex(predicate))
+
+	return nil
+}
+
+func resourceRedshiftRlsPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	if d.HasChange("using") {
+		var alterStatement = "ALTER RLS POLICY " + quoteIdentifier(d.Id()) + " USING (" + strings.TrimSpace(d.Get("using").(string)) + ")"
+
+		log.Print("Alter rls policy statement: " + alterStatement)
+
+		if _, err := redshiftClient.Exec(alterStatement); err != nil {
+			return fmt.Errorf("Could not alter rls policy %s: %s", d.Id(), err)
+		}
+	}
+
+	return readRedshiftRlsPolicyAfterApply(d, redshiftClient)
+}
+
+func resourceRedshiftRlsPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	//Cascade detaches the policy from every table, otherwise the drop fails while it is attached
+	if _, err := redshiftClient.Exec("DROP RLS POLICY " + quoteIdentifier(d.Id()) + " CASCADE"); err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
+
+func readRlsPolicyPredicate(q Queryer, name string) (string, error) {
+
+	var predicate sql.NullString
+
+	err := q.QueryRow("SELECT polqual FROM svv_rls_policy WHERE poldb = current_database() AND polname = $1", name).Scan(&predicate)
+
+	return predicate.String, err
+}
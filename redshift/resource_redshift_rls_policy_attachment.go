@@ -0,0 +1,186 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_ATTACH_RLS_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_DETACH_RLS_POLICY.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_SVV_RLS_ATTACHED_POLICY.html
+
+/*
+Attaches a policy to one table for one user or role, like redshift_masking_policy_attachment.
+Everything is immutable, so any change detaches and attaches again.
+The id is policy:schema.table:user:usesysid or policy:schema.table:role:role_id.
+*/
+func redshiftRlsPolicyAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftRlsPolicyAttachmentCreate,
+		Read:   resourceRedshiftRlsPolicyAttachmentRead,
+		Delete: resourceRedshiftRlsPolicyAttachmentDelete,
+
+		Schema: map[string]*schema.Schema{
+			"policy_name": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"schema": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"table": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			//Exactly one of user_id and role_id has to be set
+			"user_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"role_id"},
+			},
+			"role_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"user_id"},
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceRedshiftRlsPolicyAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	grantee, err := resolvePolicyGrantee(redshiftClient, d)
+	if err != nil {
+		return err
+	}
+
+	var attachStatement = "ATTACH RLS POLICY " + quoteIdentifier(d.Get("policy_name").(string)) +
+		" ON " + qualifiedTableName(d) + " TO " + grantee.clause()
+
+	log.Print("Attach rls policy statement: " + attachStatement)
+
+	if _, err := redshiftClient.Exec(attachStatement); err != nil {
+		return fmt.Errorf("Could not attach rls policy %s: %s", d.Get("policy_name").(string), err)
+	}
+
+	d.SetId(d.Get("policy_name").(string) + ":" + d.Get("schema").(string) + "." + d.Get("table").(string) + ":" + grantee.kind + ":" + strconv.Itoa(grantee.id))
+
+	return resourceRedshiftRlsPolicyAttachmentRead(d, meta)
+}
+
+// Everything is in the id and immutable, so Read only checks the attachment is still there
+func resourceRedshiftRlsPolicyAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	grantee, err := resolvePolicyGrantee(redshiftClient, d)
+	if err == sql.ErrNoRows {
+		//The user or role has been dropped, which detaches the policy
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var count int
+
+	err = redshiftClient.QueryRow(`SELECT count(*) FROM svv_rls_attached_policy
+		WHERE polname = $1 AND relschema = $2 AND relname = $3 AND grantee = $4 AND granteekind = $5`,
+		d.Get("policy_name").(string), d.Get("schema").(string), d.Get("table").(string), grantee.name, grantee.kind).Scan(&count)
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+	if count == 0 {
+		log.Printf("Rls policy attachment %s no longer exists", d.Id())
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceRedshiftRlsPolicyAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	grantee, err := resolvePolicyGrantee(redshiftClient, d)
+	if err == sql.ErrNoRows {
+		//The user or role has been dropped, which already detached the policy
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var detachStatement = "DETACH RLS POLICY " + quoteIdentifier(d.Get("policy_name").(string)) +
+		" ON " + qualifiedTableName(d) + " FROM " + grantee.clause()
+
+	log.Print("Detach rls policy statement: " + detachStatement)
+
+	if _, err := redshiftClient.Exec(detachStatement); err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
+
+// The user or role a policy is attached to
+type policyGrantee struct {
+	kind string // user or role, as in the granteekind columns of the svv_ views
+	id   int
+	name string
+}
+
+func (g policyGrantee) clause() string {
+	if g.kind == "role" {
+		return "ROLE " + quoteIdentifier(g.name)
+	}
+	return quoteIdentifier(g.name)
+}
+
+// Resolves the user_id or role_id of d. Returns sql.ErrNoRows if the user or role doesn't exist
+func resolvePolicyGrantee(q Queryer, d *schema.ResourceData) (policyGrantee, error) {
+
+	if v, ok := d.GetOk("user_id"); ok {
+		var grantee = policyGrantee{kind: "user", id: v.(int)}
+		err := q.QueryRow("SELECT usename FROM pg_user_info WHERE usesysid = $1", grantee.id).Scan(&grantee.name)
+		return grantee, err
+	}
+	if v, ok := d.GetOk("role_id"); ok {
+		var grantee = policyGrantee{kind: "role", id: v.(int)}
+		err := q.QueryRow("SELECT role_name FROM svv_roles WHERE role_id = $1", grantee.id).Scan(&grantee.name)
+		return grantee, err
+	}
+	return policyGrantee{}, NewError("One of user_id or role_id has to be set")
+}
@@ -0,0 +1,85 @@
+package redshift
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResolvePolicyGrantee(t *testing.T) {
+	cases := []struct {
+		name     string
+		raw      map[string]interface{}
+		expected policyGrantee
+		clause   string
+		err      error
+	}{
+		{"user", map[string]interface{}{"user_id": 100}, policyGrantee{kind: "user", id: 100, name: "etl"}, `"etl"`, nil},
+		{"role", map[string]interface{}{"role_id": 200}, policyGrantee{kind: "role", id: 200, name: "analyst"}, `ROLE "analyst"`, nil},
+		{"dropped role", map[string]interface{}{"role_id": 201}, policyGrantee{kind: "role", id: 201}, `ROLE ""`, sql.ErrNoRows},
+	}
+
+	for _, c := range cases {
+		var roles [][]driver.Value
+		if c.err == nil {
+			roles = [][]driver.Value{{"analyst"}}
+		}
+		var db = newFakeDb(
+			fakeResult{match: "FROM pg_user_info", rows: [][]driver.Value{{"etl"}}},
+			fakeResult{match: "FROM svv_roles", rows: roles},
+		)
+		c.raw["policy_name"] = "own_region"
+		c.raw["schema"] = "sales"
+		c.raw["table"] = "orders"
+
+		grantee, err := resolvePolicyGrantee(db, schema.TestResourceDataRaw(t, redshiftRlsPolicyAttachment().Schema, c.raw))
+		if err != c.err {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.err, err)
+		}
+		if !reflect.DeepEqual(grantee, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, grantee)
+		}
+		if grantee.clause() != c.clause {
+			t.Errorf("%s: expected clause %s, got %s", c.name, c.clause, grantee.clause())
+		}
+		db.Close()
+	}
+}
+
+func TestResolvePolicyGranteeWithoutGrantee(t *testing.T) {
+	var db = newFakeDb()
+	defer db.Close()
+
+	var d = schema.TestResourceDataRaw(t, redshiftRlsPolicyAttachment().Schema, map[string]interface{}{
+		"policy_name": "own_region", "schema": "sales", "table": "orders",
+	})
+	if _, err := resolvePolicyGrantee(db, d); err == nil {
+		t.Error("expected an error when neither user_id nor role_id is set")
+	}
+}
+
+func TestResourceRedshiftRlsPolicyAttachmentCreate(t *testing.T) {
+	var db, statements = newRecordingFakeDb(
+		fakeResult{match: "FROM svv_roles", rows: [][]driver.Value{{"analyst"}}},
+		fakeResult{match: "FROM svv_rls_attached_policy", rows: [][]driver.Value{{int64(1)}}},
+	)
+	defer db.Close()
+
+	var d = schema.TestResourceDataRaw(t, redshiftRlsPolicyAttachment().Schema, map[string]interface{}{
+		"policy_name": "own_region", "schema": "sales", "table": "orders", "role_id": 200,
+	})
+	if err := resourceRedshiftRlsPolicyAttachmentCreate(d, &Client{db: db}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected = []string{`ATTACH RLS POLICY "own_region" ON "sales"."orders" TO ROLE "analyst"`}
+	if !reflect.DeepEqual(*statements, expected) {
+		t.Errorf("expected %v, got %v", expected, *statements)
+	}
+	if d.Id() != "own_region:sales.orders:role:200" {
+		t.Errorf("expected id own_region:sales.orders:role:200, got %s", d.Id())
+	}
+}
@@ -0,0 +1,42 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceRedshiftRlsPolicyReadUsingDrift(t *testing.T) {
+	const (
+		configured = "region = current_user"
+		stored     = "(region = (\"current_user\"())::text)"
+	)
+
+	cases := []struct {
+		name      string
+		usingHash string
+		expected  string
+	}{
+		{"unchanged", sha256Hex(stored), configured},
+		{"changed outside terraform", sha256Hex("true"), stored},
+		{"no hash yet", "", configured},
+	}
+
+	for _, c := range cases {
+		var db = newFakeDb(fakeResult{match: "FROM svv_rls_policy", rows: [][]driver.Value{{stored}}})
+		var d = schema.TestResourceDataRaw(t, redshiftRlsPolicy().Schema, map[string]interface{}{
+			"name": "own_region", "using": configured,
+		})
+		d.SetId("own_region")
+		d.Set("using_hash", c.usingHash)
+
+		if err := resourceRedshiftRlsPolicyRead(d, &Client{db: db}); err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if using := d.Get("using").(string); using != c.expected {
+			t.Errorf("%s: expected using %q, got %q", c.name, c.expected, using)
+		}
+		db.Close()
+	}
+}
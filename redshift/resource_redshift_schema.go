@@ -2,16 +2,12 @@ package redshift
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"time"
 )
 
-/*
-TODO
-Add database property. This will require a new connection since you can't have databse agnostic connections in redshift/postgres
-*/
-
 func redshiftSchema() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceRedshiftSchemaCreate,
@@ -35,12 +31,23 @@ func redshiftSchema() *schema.Resource {
 				Computed:    true,
 				Description: "Defaults to user specified in provider",
 			},
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Database the schema is created in. Defaults to the database specified in provider",
+			},
 			"cascade_on_delete": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Description: "Keyword that indicates to automatically drop all objects in the schema, such as tables and functions. By default it doesn't for your safety",
 				Default:     false,
 			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Set with COMMENT ON SCHEMA, eg to document ownership or data classification",
+			},
 		},
 	}
 }
@@ -48,7 +55,10 @@ func redshiftSchema() *schema.Resource {
 func resourceRedshiftSchemaExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
 	// Exists - This is called to verify a resource still exists. It is called prior to Read,
 	// and lowers the burden of Read to be able to assume the resource exists.
-	client := meta.(*Client).db
+	client, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return false, connErr
+	}
 
 	var name string
 
@@ -68,14 +78,20 @@ func resourceRedshiftSchemaExists(d *schema.ResourceData, meta interface{}) (b b
 
 func resourceRedshiftSchemaCreate(d *schema.ResourceData, meta interface{}) error {
 
-	redshiftClient := meta.(*Client).db
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
 
 	var createStatement string = "CREATE SCHEMA " + d.Get("schema_name").(string)
 
 	//If no owner is specified it defaults to client user
 	if v, ok := d.GetOk("owner"); ok {
-		var usernames = GetUsersnamesForUsesysid(redshiftClient, []interface{}{v.(int)})
-		createStatement += " AUTHORIZATION " + usernames[0]
+		username, err := GetUsernameForUsesysid(redshiftClient, v.(int))
+		if err != nil {
+			return fmt.Errorf("Could not find redshift user %d: %s", v.(int), err)
+		}
+		createStatement += " AUTHORIZATION " + username
 	}
 
 	log.Print("Create Schema statement: " + createStatement)
@@ -99,6 +115,12 @@ func resourceRedshiftSchemaCreate(d *schema.ResourceData, meta interface{}) erro
 
 	log.Print("Created schema with oid: " + oid)
 
+	if v, ok := d.GetOk("comment"); ok {
+		if err := setComment(redshiftClient, "SCHEMA", d.Get("schema_name").(string), v.(string)); err != nil {
+			return err
+		}
+	}
+
 	d.SetId(oid)
 
 	readErr := readRedshiftSchema(d, redshiftClient)
@@ -108,7 +130,10 @@ func resourceRedshiftSchemaCreate(d *schema.ResourceData, meta interface{}) erro
 
 func resourceRedshiftSchemaRead(d *schema.ResourceData, meta interface{}) error {
 
-	redshiftClient := meta.(*Client).db
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
 
 	err := readRedshiftSchema(d, redshiftClient)
 
@@ -128,15 +153,25 @@ func readRedshiftSchema(d *schema.ResourceData, db *sql.DB) error {
 		return err
 	}
 
+	comment, err := readComment(db, d.Id(), "pg_namespace")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
 	d.Set("schema_name", schemaName)
 	d.Set("owner", owner)
+	d.Set("comment", comment)
 
 	return nil
 }
 
 func resourceRedshiftSchemaUpdate(d *schema.ResourceData, meta interface{}) error {
 
-	redshiftClient := meta.(*Client).db
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
 	tx, txErr := redshiftClient.Begin()
 	if txErr != nil {
 		panic(txErr)
@@ -154,9 +189,21 @@ func resourceRedshiftSchemaUpdate(d *schema.ResourceData, meta interface{}) erro
 
 	if d.HasChange("owner") {
 
-		var username = GetUsersnamesForUsesysid(redshiftClient, []interface{}{d.Get("owner").(int)})
+		username, err := GetUsernameForUsesysid(redshiftClient, d.Get("owner").(int))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Could not find redshift user %d: %s", d.Get("owner").(int), err)
+		}
+
+		if _, err := tx.Exec("ALTER SCHEMA " + d.Get("schema_name").(string) + " OWNER TO " + username); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
 
-		if _, err := tx.Exec("ALTER SCHEMA " + d.Get("schema_name").(string) + " OWNER TO " + username[0]); err != nil {
+	if d.HasChange("comment") {
+		if err := setComment(tx, "SCHEMA", d.Get("schema_name").(string), d.Get("comment").(string)); err != nil {
+			tx.Rollback()
 			return err
 		}
 	}
@@ -174,7 +221,10 @@ func resourceRedshiftSchemaUpdate(d *schema.ResourceData, meta interface{}) erro
 
 func resourceRedshiftSchemaDelete(d *schema.ResourceData, meta interface{}) error {
 
-	client := meta.(*Client).db
+	client, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
 
 	dropSchemaQuery := "DROP SCHEMA " + d.Get("schema_name").(string)
 
@@ -192,7 +242,24 @@ func resourceRedshiftSchemaDelete(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
+// The import id is the oid or name of the schema, prefixed with the database if it isn't the provider database,
+// eg analytics:etl
 func resourceRedshiftSchemaImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, importId := splitImportDatabase(d.Id())
+
+	redshiftClient, err := meta.(*Client).Connect(database)
+	if err != nil {
+		return nil, err
+	}
+
+	oid, err := resolveImportId(redshiftClient, importId, "schema",
+		"SELECT oid, nspname FROM pg_namespace WHERE lower(nspname) = lower($1)")
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(oid)
+	d.Set("database", database)
+
 	if err := resourceRedshiftSchemaRead(d, meta); err != nil {
 		return nil, err
 	}
@@ -214,3 +281,15 @@ func GetSchemaInfoForSchemaId(q Queryer, schemaId int) (string, int, error) {
 	}
 	return name, owner, nil
 }
+
+func GetSchemaInfoForSchemaName(q Queryer, schemaName string) (int, int, error) {
+
+	var oid int
+	var owner int
+
+	err := q.QueryRow("SELECT oid, nspowner FROM pg_namespace WHERE nspname = $1", schemaName).Scan(&oid, &owner)
+	if err != nil {
+		return -1, -1, err
+	}
+	return oid, owner, nil
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -26,15 +28,51 @@ func redshiftSchemaGroupPrivilege() *schema.Resource {
 			State: resourceRedshiftSchemaGroupPrivilegeImport,
 		},
 
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    redshiftSchemaGroupPrivilegeV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceRedshiftSchemaGroupPrivilegeStateUpgradeV0,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
+			//Either the id or the name of the schema and group has to be set
+			//Names are resolved to ids on every apply, so the privilege follows a group that is recreated with the same name
 			"schema_id": {
-				Type:     schema.TypeInt,
-				Required: true,
-				ForceNew: true,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"schema_name"},
+			},
+			"schema_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"schema_id"},
+				ValidateFunc:  validateLowercaseName,
 			},
 			"group_id": {
-				Type:     schema.TypeInt,
-				Required: true,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"group_name"},
+			},
+			"group_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"group_id"},
+				ValidateFunc:  validateLowercaseName,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
 				ForceNew: true,
 			},
 			"select": {
@@ -79,36 +117,44 @@ func redshiftSchemaGroupPrivilege() *schema.Resource {
 func resourceRedshiftSchemaGroupPrivilegeExists(d *schema.ResourceData, meta interface{}) (bool, error) {
 	// Exists - This is called to verify a resource still exists. It is called prior to Read,
 	// and lowers the burden of Read to be able to assume the resource exists.
-	client := meta.(*Client).db
-
-	var privilegeId string
-
-	err := client.QueryRow(`select distinct id from (
-		select nsp.oid || '_' || pu.grosysid as id
-		from pg_group pu, pg_default_acl acl, pg_namespace nsp
-		where acl.defaclnamespace = nsp.oid and
-		array_to_string(acl.defaclacl, '|') LIKE '%' || 'group ' || pu.groname || '=%'
-		and nsp.oid || '_' || pu.grosysid = $1
-		union
-		select nsp.oid || '_' || pu.grosysid as id
-		from  pg_group pu, pg_namespace nsp
-		where array_to_string(nsp.nspacl, '|') LIKE '%' || 'group ' || pu.groname || '=%'
-			and nsp.oid || '_' || pu.grosysid = $1
-		)`,
-		d.Id()).Scan(&privilegeId)
+	client, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return false, connErr
+	}
 
+	target, targetErr := resolveSchemaGroupPrivilegeTarget(client, d)
 	switch {
-	case err == sql.ErrNoRows:
+	case targetErr == sql.ErrNoRows:
 		return false, nil
-	case err != nil:
+	case targetErr != nil:
+		return false, targetErr
+	}
+
+	aclItems, err := readAclItems(client, `select array_to_string(acl.defaclacl, '|')
+		from pg_default_acl acl
+		where acl.defaclnamespace = $1
+		union all
+		select array_to_string(nsp.nspacl, '|')
+		from pg_namespace nsp
+		where nsp.oid = $1`, target.schemaId)
+	if err != nil {
 		return false, err
 	}
-	return true, nil
+
+	for _, item := range aclItems {
+		if item.isGrantedTo(granteeTypeGroup, target.groupName) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func resourceRedshiftSchemaGroupPrivilegeCreate(d *schema.ResourceData, meta interface{}) error {
 
-	redshiftClient := meta.(*Client).db
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
 
 	tx, txErr := redshiftClient.Begin()
 
@@ -119,30 +165,24 @@ func resourceRedshiftSchemaGroupPrivilegeCreate(d *schema.ResourceData, meta int
 	grants := validateGrants(d)
 	schemaGrants := validateSchemaGrants(d)
 
-	if len(grants) == 0 && len(schemaGrants) == 0  {
+	if len(grants) == 0 && len(schemaGrants) == 0 {
 		tx.Rollback()
 		return NewError("Must have at least 1 privilege")
 	}
 
-	schemaName, schemaOwner, schemaErr := GetSchemaInfoForSchemaId(tx, d.Get("schema_id").(int))
-	if schemaErr != nil {
-		log.Print(schemaErr)
+	target, targetErr := resolveSchemaGroupPrivilegeTarget(tx, d)
+	if targetErr != nil {
+		log.Print(targetErr)
 		tx.Rollback()
-		return schemaErr
+		return targetErr
 	}
+	schemaName, groupName := target.schemaName, target.groupName
 
-	if isSystemSchema(schemaOwner) {
+	if isSystemSchema(target.schemaOwner) {
 		tx.Rollback()
 		return NewError("Privilege creation is not allowed for system schemas, schema=" + schemaName)
 	}
 
-	groupName, groupErr := GetGroupNameForGroupId(tx, d.Get("group_id").(int))
-	if groupErr != nil {
-		log.Print(groupErr)
-		tx.Rollback()
-		return groupErr
-	}
-
 	if len(grants) > 0 {
 		var grantPrivilegeStatement = "GRANT " + strings.Join(grants[:], ",") + " ON ALL TABLES IN SCHEMA " + schemaName + " TO GROUP " + groupName
 
@@ -169,7 +209,7 @@ func resourceRedshiftSchemaGroupPrivilegeCreate(d *schema.ResourceData, meta int
 		}
 	}
 
-	d.SetId(fmt.Sprint(d.Get("schema_id").(int)) + "_" + fmt.Sprint(d.Get("group_id").(int)))
+	d.SetId(target.id())
 
 	readErr := readRedshiftSchemaGroupPrivilege(d, tx)
 
@@ -184,7 +224,10 @@ func resourceRedshiftSchemaGroupPrivilegeCreate(d *schema.ResourceData, meta int
 
 func resourceRedshiftSchemaGroupPrivilegeRead(d *schema.ResourceData, meta interface{}) error {
 
-	redshiftClient := meta.(*Client).db
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
 	tx, txErr := redshiftClient.Begin()
 	if txErr != nil {
 		panic(txErr)
@@ -203,8 +246,8 @@ func resourceRedshiftSchemaGroupPrivilegeRead(d *schema.ResourceData, meta inter
 
 func readRedshiftSchemaGroupPrivilege(d *schema.ResourceData, tx *sql.Tx) error {
 	var (
-		usagePrivilege		bool
-		createPrivilege		bool
+		usagePrivilege      bool
+		createPrivilege     bool
 		selectPrivilege     bool
 		updatePrivilege     bool
 		insertPrivilege     bool
@@ -212,46 +255,48 @@ func readRedshiftSchemaGroupPrivilege(d *schema.ResourceData, tx *sql.Tx) error
 		referencesPrivilege bool
 	)
 
-	var hasPrivilegeQuery = `
-			select
-			decode(charindex('r',split_part(split_part(array_to_string(defaclacl, '|'),'group ' || pu.groname,2 ) ,'/',1)),0,0,1)  as select,
-			decode(charindex('w',split_part(split_part(array_to_string(defaclacl, '|'),'group ' || pu.groname,2 ) ,'/',1)),0,0,1)  as update,
-			decode(charindex('a',split_part(split_part(array_to_string(defaclacl, '|'),'group ' || pu.groname,2 ) ,'/',1)),0,0,1)  as insert,
-			decode(charindex('d',split_part(split_part(array_to_string(defaclacl, '|'),'group ' || pu.groname,2 ) ,'/',1)),0,0,1)  as delete,
-			decode(charindex('x',split_part(split_part(array_to_string(defaclacl, '|'),'group ' || pu.groname,2 ) ,'/',1)),0,0,1)  as references
-			from pg_group pu, pg_default_acl acl, pg_namespace nsp
-			where acl.defaclnamespace = nsp.oid and
-			array_to_string(acl.defaclacl, '|') LIKE '%' || 'group ' || pu.groname || '=%'
-			and nsp.oid = $1
-			and pu.grosysid = $2`
-
-	privilegesError := tx.QueryRow(hasPrivilegeQuery, d.Get("schema_id").(int), d.Get("group_id").(int)).Scan(&selectPrivilege, &updatePrivilege, &insertPrivilege, &deletePrivilege, &referencesPrivilege)
-
-	if privilegesError != nil && privilegesError != sql.ErrNoRows {
-		tx.Rollback()
-		return privilegesError
-	}
-
-	var hasSchemaPrivilegeQuery = `
-			select 
-			case 
-				when charindex('U',split_part(split_part(array_to_string(nspacl, '|'), 'group ' || pu.groname,2 ) ,'/',1)) > 0 then 1 
-				else 0 
-			end as usage,
-			case 
-				when charindex('C',split_part(split_part(array_to_string(nspacl, '|'),'group ' || pu.groname,2 ) ,'/',1)) > 0 then 1 
-				else 0 
-			end as create
-			from pg_group pu, pg_namespace nsp
-			where array_to_string(nsp.nspacl, '|') LIKE '%' || 'group ' || pu.groname || '=%'
-			and nsp.oid = $1
-			and pu.grosysid = $2`
-
-	schemaPrivilegesError := tx.QueryRow(hasSchemaPrivilegeQuery, d.Get("schema_id").(int), d.Get("group_id").(int)).Scan(&usagePrivilege, &createPrivilege)
-
-	if schemaPrivilegesError != nil && schemaPrivilegesError != sql.ErrNoRows {
-		tx.Rollback()
-		return schemaPrivilegesError
+	target, targetErr := resolveSchemaGroupPrivilegeTarget(tx, d)
+	if targetErr != nil {
+		log.Print(targetErr)
+		return targetErr
+	}
+
+	//The ids change if the schema or group was recreated and is addressed by name
+	d.SetId(target.id())
+	d.Set("schema_id", target.schemaId)
+	d.Set("schema_name", target.schemaName)
+	d.Set("group_id", target.groupId)
+	d.Set("group_name", target.groupName)
+
+	//Default privileges on tables, whoever defined them
+	defaultAclItems, err := readAclItems(tx, `select array_to_string(acl.defaclacl, '|')
+			from pg_default_acl acl
+			where acl.defaclnamespace = $1 and acl.defaclobjtype = 'r'`, target.schemaId)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range defaultAclItems {
+		if !item.isGrantedTo(granteeTypeGroup, target.groupName) {
+			continue
+		}
+		selectPrivilege = selectPrivilege || item.hasPrivilege('r')
+		updatePrivilege = updatePrivilege || item.hasPrivilege('w')
+		insertPrivilege = insertPrivilege || item.hasPrivilege('a')
+		deletePrivilege = deletePrivilege || item.hasPrivilege('d')
+		referencesPrivilege = referencesPrivilege || item.hasPrivilege('x')
+	}
+
+	schemaAclItems, err := readAclItems(tx, "select array_to_string(nsp.nspacl, '|') from pg_namespace nsp where nsp.oid = $1", target.schemaId)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range schemaAclItems {
+		if item.isGrantedTo(granteeTypeGroup, target.groupName) {
+			usagePrivilege = item.hasPrivilege('U')
+			createPrivilege = item.hasPrivilege('C')
+		}
 	}
 
 	d.Set("usage", usagePrivilege)
@@ -266,7 +311,10 @@ func readRedshiftSchemaGroupPrivilege(d *schema.ResourceData, tx *sql.Tx) error
 }
 
 func resourceRedshiftSchemaGroupPrivilegeUpdate(d *schema.ResourceData, meta interface{}) error {
-	redshiftClient := meta.(*Client).db
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
 	tx, txErr := redshiftClient.Begin()
 
 	if txErr != nil {
@@ -276,24 +324,18 @@ func resourceRedshiftSchemaGroupPrivilegeUpdate(d *schema.ResourceData, meta int
 	grants := validateGrants(d)
 	schemaGrants := validateSchemaGrants(d)
 
-	if len(grants) == 0 && len(schemaGrants) == 0  {
+	if len(grants) == 0 && len(schemaGrants) == 0 {
 		tx.Rollback()
 		return NewError("Must have at least 1 privilege")
 	}
 
-	schemaName, _, schemaErr := GetSchemaInfoForSchemaId(tx, d.Get("schema_id").(int))
-	if schemaErr != nil {
-		log.Print(schemaErr)
-		tx.Rollback()
-		return schemaErr
-	}
-
-	groupName, groupErr := GetGroupNameForGroupId(tx, d.Get("group_id").(int))
-	if groupErr != nil {
-		log.Print(groupErr)
+	target, targetErr := resolveSchemaGroupPrivilegeTarget(tx, d)
+	if targetErr != nil {
+		log.Print(targetErr)
 		tx.Rollback()
-		return groupErr
+		return targetErr
 	}
+	schemaName, groupName := target.schemaName, target.groupName
 
 	//Would be much nicer to do this with zip if possible
 	if err := updatePrivilege(tx, d, "select", "SELECT", schemaName, groupName); err != nil {
@@ -331,26 +373,23 @@ func resourceRedshiftSchemaGroupPrivilegeUpdate(d *schema.ResourceData, meta int
 
 func resourceRedshiftSchemaGroupPrivilegeDelete(d *schema.ResourceData, meta interface{}) error {
 
-	redshiftClient := meta.(*Client).db
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
 	tx, txErr := redshiftClient.Begin()
 
 	if txErr != nil {
 		panic(txErr)
 	}
 
-	schemaName, _, schemaErr := GetSchemaInfoForSchemaId(tx, d.Get("schema_id").(int))
-	if schemaErr != nil {
-		log.Print(schemaErr)
-		tx.Rollback()
-		return schemaErr
-	}
-
-	groupName, groupErr := GetGroupNameForGroupId(tx, d.Get("group_id").(int))
-	if groupErr != nil {
-		log.Print(groupErr)
+	target, targetErr := resolveSchemaGroupPrivilegeTarget(tx, d)
+	if targetErr != nil {
+		log.Print(targetErr)
 		tx.Rollback()
-		return groupErr
+		return targetErr
 	}
+	schemaName, groupName := target.schemaName, target.groupName
 
 	if _, err := tx.Exec("REVOKE ALL ON  ALL TABLES IN SCHEMA " + schemaName + " FROM GROUP " + groupName); err != nil {
 		tx.Rollback()
@@ -371,13 +410,155 @@ func resourceRedshiftSchemaGroupPrivilegeDelete(d *schema.ResourceData, meta int
 	return nil
 }
 
+// Parses the acls returned by query, one per row
+func readAclItems(q Queryer, query string, args ...interface{}) ([]aclItem, error) {
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []aclItem
+	for rows.Next() {
+		var acl sql.NullString
+		if err := rows.Scan(&acl); err != nil {
+			return nil, err
+		}
+
+		aclItems, err := parseAcl(acl.String)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, aclItems...)
+	}
+	return items, rows.Err()
+}
+
+// The import id is either the id, schemaoid_grosysid, or schema_name:group_name
+// The import id is schema_name:group_name or schemaoid_grosysid, prefixed with the database if it isn't the
+// provider database, eg analytics:etl:loaders
 func resourceRedshiftSchemaGroupPrivilegeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+
+	database, parts, err := splitSchemaGroupPrivilegeImportId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	redshiftClient, err := meta.(*Client).Connect(database)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaId, err := resolveImportId(redshiftClient, parts[0], "schema",
+		"SELECT oid, nspname FROM pg_namespace WHERE lower(nspname) = lower($1)")
+	if err != nil {
+		return nil, err
+	}
+	groupId, err := resolveImportId(redshiftClient, parts[1], "group",
+		"SELECT grosysid, groname FROM pg_group WHERE lower(groname) = lower($1)")
+	if err != nil {
+		return nil, err
+	}
+
+	schemaOid, _ := strconv.Atoi(schemaId)
+	grosysid, _ := strconv.Atoi(groupId)
+
+	d.Set("schema_id", schemaOid)
+	d.Set("group_id", grosysid)
+	d.Set("database", database)
+	d.SetId(schemaId + "_" + groupId)
+
 	if err := resourceRedshiftSchemaGroupPrivilegeRead(d, meta); err != nil {
 		return nil, err
 	}
 	return []*schema.ResourceData{d}, nil
 }
 
+// Returns the database, empty for the provider database, and the schema and group, either as names or ids
+func splitSchemaGroupPrivilegeImportId(importId string) (string, []string, error) {
+
+	var (
+		database string
+		parts    = strings.Split(importId, ":")
+	)
+
+	switch {
+	case len(parts) == 3:
+		database, parts = parts[0], parts[1:]
+	case len(parts) == 2 && schemaGroupPrivilegeIdRegex.MatchString(parts[1]):
+		database, parts = parts[0], strings.SplitN(parts[1], "_", 2)
+	case len(parts) == 1:
+		parts = strings.SplitN(importId, "_", 2)
+	}
+
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", nil, fmt.Errorf("Could not import schema group privilege %s: expected [database:]schema_name:group_name or [database:]schemaoid_grosysid", importId)
+	}
+	return database, parts, nil
+}
+
+// The id of the resource, schemaoid_grosysid
+var schemaGroupPrivilegeIdRegex = regexp.MustCompile(`^[0-9]+_[0-9]+$`)
+
+// The schema and group a privilege is granted on and to
+type schemaGroupPrivilegeTarget struct {
+	schemaId    int
+	schemaName  string
+	schemaOwner int
+	groupId     int
+	groupName   string
+}
+
+func (t schemaGroupPrivilegeTarget) id() string {
+	return strconv.Itoa(t.schemaId) + "_" + strconv.Itoa(t.groupId)
+}
+
+// Ids are used as long as they exist. Names are used when no id is known yet, or when the schema or group
+// has been recreated with a new id. Returns sql.ErrNoRows if either doesn't exist
+func resolveSchemaGroupPrivilegeTarget(q Queryer, d *schema.ResourceData) (schemaGroupPrivilegeTarget, error) {
+	var (
+		target = schemaGroupPrivilegeTarget{schemaId: -1, groupId: -1}
+		err    error
+	)
+
+	if v, ok := d.GetOk("schema_id"); ok {
+		target.schemaId = v.(int)
+		target.schemaName, target.schemaOwner, err = GetSchemaInfoForSchemaId(q, target.schemaId)
+	}
+	if target.schemaName == "" && (err == nil || err == sql.ErrNoRows) {
+		if v, ok := d.GetOk("schema_name"); ok {
+			target.schemaName = v.(string)
+			target.schemaId, target.schemaOwner, err = GetSchemaInfoForSchemaName(q, target.schemaName)
+		}
+	}
+	if err != nil {
+		return target, err
+	}
+	if target.schemaId == -1 {
+		return target, NewError("Either schema_id or schema_name has to be set")
+	}
+
+	if v, ok := d.GetOk("group_id"); ok {
+		target.groupId = v.(int)
+		target.groupName, err = GetGroupNameForGroupId(q, target.groupId)
+	}
+	if target.groupName == "" && (err == nil || err == sql.ErrNoRows) {
+		if v, ok := d.GetOk("group_name"); ok {
+			target.groupName = v.(string)
+			target.groupId, err = GetGroupIdForGroupName(q, target.groupName)
+		}
+	}
+	if err != nil {
+		return target, err
+	}
+	if target.groupId == -1 {
+		return target, NewError("Either group_id or group_name has to be set")
+	}
+
+	return target, nil
+}
+
 func updatePrivilege(tx *sql.Tx, d *schema.ResourceData, attribute string, privilege string, schemaName string, groupName string) error {
 	if !d.HasChange(attribute) {
 		return nil
@@ -457,6 +638,73 @@ func validateSchemaGrants(d *schema.ResourceData) []string {
 	return grants
 }
 
+// The schema before schema_name and group_name were added
+func redshiftSchemaGroupPrivilegeV0() *schema.Resource {
+	var privilegeSchema = map[string]*schema.Schema{
+		"schema_id": {
+			Type:     schema.TypeInt,
+			Required: true,
+			ForceNew: true,
+		},
+		"group_id": {
+			Type:     schema.TypeInt,
+			Required: true,
+			ForceNew: true,
+		},
+	}
+	for _, privilege := range []string{"select", "insert", "update", "delete", "references", "create", "usage"} {
+		privilegeSchema[privilege] = &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		}
+	}
+	return &schema.Resource{Schema: privilegeSchema}
+}
+
+// Adds the names of the schema and group. If the provider isn't configured yet they are left empty,
+// the next refresh reads them
+func resourceRedshiftSchemaGroupPrivilegeStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+
+	rawState["schema_name"] = ""
+	rawState["group_name"] = ""
+
+	client, ok := meta.(*Client)
+	if !ok || client == nil {
+		return rawState, nil
+	}
+
+	//v0 privileges were always in the database of the provider
+	db := client.db
+
+	if schemaId, ok := rawStateInt(rawState["schema_id"]); ok {
+		if schemaName, _, err := GetSchemaInfoForSchemaId(db, schemaId); err == nil {
+			rawState["schema_name"] = schemaName
+		}
+	}
+	if groupId, ok := rawStateInt(rawState["group_id"]); ok {
+		if groupName, err := GetGroupNameForGroupId(db, groupId); err == nil {
+			rawState["group_name"] = groupName
+		}
+	}
+
+	return rawState, nil
+}
+
+// Numbers in raw state are decoded from json
+func rawStateInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	}
+	return 0, false
+}
+
 // errorString is a trivial implementation of error.
 type errorString struct {
 	s string
@@ -0,0 +1,118 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestRawStateInt(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected int
+		ok       bool
+	}{
+		{100, 100, true},
+		{float64(100), 100, true},
+		{"100", 100, true},
+		{"abc", 0, false},
+		{nil, 0, false},
+		{true, 0, false},
+	}
+
+	for _, c := range cases {
+		if i, ok := rawStateInt(c.value); i != c.expected || ok != c.ok {
+			t.Errorf("rawStateInt(%v): expected %d, %t, got %d, %t", c.value, c.expected, c.ok, i, ok)
+		}
+	}
+}
+
+// State written before schema_name and group_name were added, with numbers decoded from json
+func testSchemaGroupPrivilegeStateV0() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         "100_200",
+		"schema_id":  float64(100),
+		"group_id":   float64(200),
+		"select":     true,
+		"insert":     false,
+		"update":     false,
+		"delete":     false,
+		"references": false,
+		"create":     false,
+		"usage":      true,
+	}
+}
+
+func TestResourceRedshiftSchemaGroupPrivilegeStateUpgradeV0(t *testing.T) {
+	var upgraders = redshiftSchemaGroupPrivilege().StateUpgraders
+	if len(upgraders) != 1 || upgraders[0].Version != 0 {
+		t.Fatalf("expected one upgrader from version 0, got %v", upgraders)
+	}
+
+	var db = newFakeDb(
+		fakeResult{match: "pg_namespace", rows: [][]driver.Value{{"etl", int64(100)}}},
+		fakeResult{match: "pg_group", rows: [][]driver.Value{{"loaders"}}},
+	)
+	defer db.Close()
+
+	upgraded, err := upgraders[0].Upgrade(testSchemaGroupPrivilegeStateV0(), &Client{db: db})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected = testSchemaGroupPrivilegeStateV0()
+	expected["schema_name"] = "etl"
+	expected["group_name"] = "loaders"
+
+	if !reflect.DeepEqual(upgraded, expected) {
+		t.Fatalf("expected %v, got %v", expected, upgraded)
+	}
+}
+
+func TestResourceRedshiftSchemaGroupPrivilegeStateUpgradeV0Unconfigured(t *testing.T) {
+	upgraded, err := resourceRedshiftSchemaGroupPrivilegeStateUpgradeV0(testSchemaGroupPrivilegeStateV0(), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected = testSchemaGroupPrivilegeStateV0()
+	expected["schema_name"] = ""
+	expected["group_name"] = ""
+
+	if !reflect.DeepEqual(upgraded, expected) {
+		t.Fatalf("expected %v, got %v", expected, upgraded)
+	}
+}
+
+func TestSplitSchemaGroupPrivilegeImportId(t *testing.T) {
+	cases := []struct {
+		importId string
+		database string
+		parts    []string
+		err      bool
+	}{
+		{"100_200", "", []string{"100", "200"}, false},
+		{"etl:loaders", "", []string{"etl", "loaders"}, false},
+		{"etl:etl_loaders", "", []string{"etl", "etl_loaders"}, false},
+		{"analytics:100_200", "analytics", []string{"100", "200"}, false},
+		{"analytics:etl:loaders", "analytics", []string{"etl", "loaders"}, false},
+		{"etl", "", nil, true},
+		{"etl:", "", nil, true},
+		{"a:b:c:d", "", nil, true},
+	}
+
+	for _, c := range cases {
+		database, parts, err := splitSchemaGroupPrivilegeImportId(c.importId)
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s %v", c.importId, database, parts)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: err: %s", c.importId, err)
+		} else if database != c.database || !reflect.DeepEqual(parts, c.parts) {
+			t.Errorf("%s: expected %s %v, got %s %v", c.importId, c.database, c.parts, database, parts)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package redshift
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+/*
+Makes sure every table, view, function and procedure in a schema is owned by one user, using the same discovery as
+reassigning the objects of a dropped user. Every refresh lists the objects owned by someone else in
+non_compliant_objects, and the next apply transfers them. Materialized views are left alone as their owner can't be
+changed. The schema itself is not transferred, use redshift_schema.owner for that.
+The id is the oid of the schema. Deleting the resource leaves the owners as they are.
+*/
+func redshiftSchemaOwnership() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceRedshiftSchemaOwnershipCreate,
+		Read:          resourceRedshiftSchemaOwnershipRead,
+		Update:        resourceRedshiftSchemaOwnershipUpdate,
+		Delete:        resourceRedshiftSchemaOwnershipDelete,
+		CustomizeDiff: resourceRedshiftSchemaOwnershipCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"schema_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"owner": { //usesysid
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"non_compliant_objects": { //eg table etl.orders, owned by someone else at the last refresh
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+var schemaOwnershipObjectTypes = map[string]bool{
+	"table":     true,
+	"view":      true,
+	"function":  true,
+	"procedure": true,
+}
+
+func resourceRedshiftSchemaOwnershipCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	schemaOid, _, err := GetSchemaInfoForSchemaName(redshiftClient, d.Get("schema_name").(string))
+	if err != nil {
+		return fmt.Errorf("Could not find schema %s: %s", d.Get("schema_name").(string), err)
+	}
+
+	d.SetId(strconv.Itoa(schemaOid))
+
+	return resourceRedshiftSchemaOwnershipUpdate(d, meta)
+}
+
+func resourceRedshiftSchemaOwnershipRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	objects, err := findNonCompliantObjects(redshiftClient, d.Get("schema_name").(string), d.Get("owner").(int))
+	if err != nil {
+		return err
+	}
+
+	var descriptions = []string{}
+	for _, object := range objects {
+		descriptions = append(descriptions, object.String())
+	}
+
+	d.Set("non_compliant_objects", descriptions)
+
+	return nil
+}
+
+func resourceRedshiftSchemaOwnershipUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	var (
+		schemaName = d.Get("schema_name").(string)
+		owner      = d.Get("owner").(int)
+	)
+
+	username, err := GetUsernameForUsesysid(tx, owner)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Could not find redshift user %d: %s", owner, err)
+	}
+
+	objects, err := findNonCompliantObjects(tx, schemaName, owner)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, object := range objects {
+		log.Print("Transferring ownership: " + object.reassignStatement + username)
+		if _, err := tx.Exec(object.reassignStatement + username); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Could not transfer %s to %s: %s", object, username, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return resourceRedshiftSchemaOwnershipRead(d, meta)
+}
+
+func resourceRedshiftSchemaOwnershipDelete(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceRedshiftSchemaOwnershipCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	//New objects owned by someone else were found by the last refresh
+	if len(d.Get("non_compliant_objects").([]interface{})) > 0 {
+		return d.SetNewComputed("non_compliant_objects")
+	}
+	return nil
+}
+
+func findNonCompliantObjects(q Queryer, schemaName string, owner int) ([]ownedObject, error) {
+	return findOwnedObjects(q, false, func(o ownedObject) bool {
+		return o.schemaName == schemaName && schemaOwnershipObjectTypes[o.objectType] && o.owner != owner
+	})
+}
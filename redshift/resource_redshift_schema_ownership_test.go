@@ -0,0 +1,36 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"testing"
+)
+
+func TestFindNonCompliantObjects(t *testing.T) {
+	var db = newFakeDb(testOwnedObjectResults([][]driver.Value{{"etl ", "daily_orders ", "etl "}})...)
+	defer db.Close()
+
+	//Everything in testOwnedObjectResults is owned by user 100
+	objects, err := findNonCompliantObjects(db, "etl", 101)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	//The schema itself and the materialized view are left out
+	var expected = []string{"function etl.f(integer)", "table etl.orders"}
+	if len(objects) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, objects)
+	}
+	for i, object := range objects {
+		if object.String() != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], object)
+		}
+	}
+
+	objects, err = findNonCompliantObjects(db, "etl", 100)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(objects) != 0 {
+		t.Errorf("expected no objects, got %v", objects)
+	}
+}
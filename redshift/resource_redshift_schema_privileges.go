@@ -0,0 +1,608 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_GRANT.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_REVOKE.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_DEFAULT_PRIVILEGES.html
+
+/*
+Authoritative privileges on a schema, its tables and views, and the default privileges defined in it.
+The grant blocks are the complete set: every refresh compares them with nspacl, relacl and pg_default_acl,
+lists the differences in non_compliant_privileges, and the next apply grants what is missing and revokes
+anything else, whoever granted it, including grant options. The privileges of the owner of each object are left alone.
+Declared table privileges are granted on all existing tables and as default privileges of the provider user.
+Default privileges on functions and procedures in the schema are always revoked.
+redshift_schema_group_privilege only manages the privileges in its config, don't use both for the same schema.
+The id is the oid of the schema. Deleting the resource revokes everything from the declared grantees.
+*/
+func redshiftSchemaPrivileges() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceRedshiftSchemaPrivilegesCreate,
+		Read:          resourceRedshiftSchemaPrivilegesRead,
+		Update:        resourceRedshiftSchemaPrivilegesUpdate,
+		Delete:        resourceRedshiftSchemaPrivilegesDelete,
+		CustomizeDiff: resourceRedshiftSchemaPrivilegesCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"schema_name": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"grant": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"grantee_type": { //user, group, role or public
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{granteeTypeUser, granteeTypeGroup, granteeTypeRole, granteeTypePublic}, false),
+						},
+						"grantee": { //The name of the user, group or role, empty for public
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateLowercaseName,
+						},
+						"schema_privileges": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice([]string{"USAGE", "CREATE"}, false),
+							},
+						},
+						"table_privileges": { //On all tables and views, and on tables created later by the provider user
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice([]string{"SELECT", "INSERT", "UPDATE", "DELETE", "REFERENCES", "DROP"}, false),
+							},
+						},
+					},
+				},
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"non_compliant_privileges": { //eg table etl.orders: revoke INSERT from group loaders, at the last refresh
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// A declared grant, with privileges as acl letters, eg UC
+type schemaPrivilegeGrant struct {
+	grantee          aclItem
+	schemaPrivileges string
+	tablePrivileges  string
+}
+
+// An object with an acl, and how to write GRANT and REVOKE statements for it
+type schemaAclObject struct {
+	description string // eg table etl.orders
+	prefix      string // eg ALTER DEFAULT PRIVILEGES FOR USER etl IN SCHEMA etl
+	on          string // eg SCHEMA etl, or TABLES for default privileges
+	owner       string // The user whose own privileges are left alone
+	acl         []aclItem
+	expected    func(g schemaPrivilegeGrant) string
+	revokeOnly  bool // Missing privileges are not granted, eg default privileges of other users
+}
+
+type schemaPrivilegeChange struct {
+	grantee     aclItem
+	description string
+	statement   string
+}
+
+func resourceRedshiftSchemaPrivilegesCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	schemaOid, schemaOwner, err := GetSchemaInfoForSchemaName(redshiftClient, d.Get("schema_name").(string))
+	if err != nil {
+		return fmt.Errorf("Could not find schema %s: %s", d.Get("schema_name").(string), err)
+	}
+
+	if isSystemSchema(schemaOwner) {
+		return NewError("Privilege creation is not allowed for system schemas, schema=" + d.Get("schema_name").(string))
+	}
+
+	d.SetId(strconv.Itoa(schemaOid))
+
+	return resourceRedshiftSchemaPrivilegesUpdate(d, meta)
+}
+
+func resourceRedshiftSchemaPrivilegesRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	schemaOid, _, err := GetSchemaInfoForSchemaName(redshiftClient, d.Get("schema_name").(string))
+	if err == sql.ErrNoRows {
+		log.Printf("Schema %s no longer exists", d.Get("schema_name").(string))
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	grants, err := expandSchemaPrivilegeGrants(d)
+	if err != nil {
+		return err
+	}
+
+	changes, err := planSchemaPrivilegeChanges(redshiftClient, schemaOid, d.Get("schema_name").(string), grants)
+	if err != nil {
+		return err
+	}
+
+	var descriptions = []string{}
+	for _, change := range changes {
+		descriptions = append(descriptions, change.description)
+	}
+
+	d.SetId(strconv.Itoa(schemaOid))
+	d.Set("non_compliant_privileges", descriptions)
+
+	return nil
+}
+
+func resourceRedshiftSchemaPrivilegesUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	grants, err := expandSchemaPrivilegeGrants(d)
+	if err != nil {
+		return err
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	var schemaName = d.Get("schema_name").(string)
+
+	schemaOid, _, err := GetSchemaInfoForSchemaName(tx, schemaName)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Could not find schema %s: %s", schemaName, err)
+	}
+
+	changes, err := planSchemaPrivilegeChanges(tx, schemaOid, schemaName, grants)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, change := range changes {
+		log.Print("Schema privileges statement: " + change.statement)
+		if _, err := tx.Exec(change.statement); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Could not apply %s: %s", change.description, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return resourceRedshiftSchemaPrivilegesRead(d, meta)
+}
+
+// Revokes everything the declared grantees have on the schema, its tables and its default privileges
+func resourceRedshiftSchemaPrivilegesDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	grants, err := expandSchemaPrivilegeGrants(d)
+	if err != nil {
+		return err
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	var schemaName = d.Get("schema_name").(string)
+
+	schemaOid, _, err := GetSchemaInfoForSchemaName(tx, schemaName)
+	if err == sql.ErrNoRows {
+		//The schema has been dropped, and the privileges with it
+		tx.Rollback()
+		return nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Could not find schema %s: %s", schemaName, err)
+	}
+
+	changes, err := planSchemaPrivilegeChanges(tx, schemaOid, schemaName, nil)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, change := range changes {
+		if _, declared := findSchemaPrivilegeGrant(grants, change.grantee); !declared {
+			continue
+		}
+		log.Print("Schema privileges statement: " + change.statement)
+		if _, err := tx.Exec(change.statement); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Could not apply %s: %s", change.description, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func resourceRedshiftSchemaPrivilegesCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	//Privileges granted or revoked outside terraform were found by the last refresh, or the grants change
+	if d.HasChange("grant") || len(d.Get("non_compliant_privileges").([]interface{})) > 0 {
+		return d.SetNewComputed("non_compliant_privileges")
+	}
+	return nil
+}
+
+func expandSchemaPrivilegeGrants(d *schema.ResourceData) ([]schemaPrivilegeGrant, error) {
+
+	var (
+		grants []schemaPrivilegeGrant
+		seen   = map[string]bool{}
+	)
+
+	for _, v := range d.Get("grant").(*schema.Set).List() {
+		var m = v.(map[string]interface{})
+
+		var grant = schemaPrivilegeGrant{
+			grantee:          aclItem{granteeType: m["grantee_type"].(string), grantee: m["grantee"].(string)},
+			schemaPrivileges: aclPrivilegeLetters(m["schema_privileges"].(*schema.Set).List()),
+			tablePrivileges:  aclPrivilegeLetters(m["table_privileges"].(*schema.Set).List()),
+		}
+
+		if grant.grantee.granteeType == granteeTypePublic {
+			grant.grantee.grantee = ""
+		} else if grant.grantee.grantee == "" {
+			return nil, fmt.Errorf("Could not read grants of schema %s: grantee has to be set for grantee_type %s", d.Get("schema_name").(string), grant.grantee.granteeType)
+		}
+
+		var key = grant.grantee.granteeType + ":" + grant.grantee.grantee
+		if seen[key] {
+			return nil, fmt.Errorf("Could not read grants of schema %s: %s is declared more than once", d.Get("schema_name").(string), grant.grantee.granteeClause())
+		}
+		seen[key] = true
+
+		grants = append(grants, grant)
+	}
+	return grants, nil
+}
+
+// Finds the GRANT and REVOKE statements that make the schema, its tables and views and its default privileges
+// match the grants
+func planSchemaPrivilegeChanges(q Queryer, schemaOid int, schemaName string, grants []schemaPrivilegeGrant) ([]schemaPrivilegeChange, error) {
+
+	var objects []schemaAclObject
+
+	var (
+		schemaOwner sql.NullString
+		schemaAcl   sql.NullString
+	)
+	err := q.QueryRow(`SELECT pu.usename, array_to_string(nsp.nspacl, '|')
+		FROM pg_namespace nsp
+		LEFT JOIN pg_user pu ON pu.usesysid = nsp.nspowner
+		WHERE nsp.oid = $1`, schemaOid).Scan(&schemaOwner, &schemaAcl)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read privileges on schema %s: %s", schemaName, err)
+	}
+
+	schemaAclItems, err := parseAcl(schemaAcl.String)
+	if err != nil {
+		return nil, err
+	}
+
+	objects = append(objects, schemaAclObject{
+		description: "schema " + schemaName,
+		on:          "SCHEMA " + quoteIdentifier(schemaName),
+		owner:       schemaOwner.String,
+		acl:         schemaAclItems,
+		expected:    func(g schemaPrivilegeGrant) string { return g.schemaPrivileges },
+	})
+
+	tableObjects, err := readSchemaTableAclObjects(q, schemaOid, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read privileges on tables in schema %s: %s", schemaName, err)
+	}
+	objects = append(objects, tableObjects...)
+
+	defaultObjects, err := readSchemaDefaultAclObjects(q, schemaOid, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read default privileges in schema %s: %s", schemaName, err)
+	}
+	objects = append(objects, defaultObjects...)
+
+	var changes []schemaPrivilegeChange
+	for _, object := range objects {
+		changes = append(changes, object.changes(grants)...)
+	}
+	return changes, nil
+}
+
+func readSchemaTableAclObjects(q Queryer, schemaOid int, schemaName string) ([]schemaAclObject, error) {
+
+	rows, err := q.Query(`SELECT c.relname, pu.usename, array_to_string(c.relacl, '|')
+		FROM pg_class c
+		LEFT JOIN pg_user pu ON pu.usesysid = c.relowner
+		WHERE c.relnamespace = $1 AND c.relkind IN ('r', 'v')
+		ORDER BY c.relname`, schemaOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []schemaAclObject
+	for rows.Next() {
+		var (
+			tableName string
+			owner     sql.NullString
+			acl       sql.NullString
+		)
+		if err := rows.Scan(&tableName, &owner, &acl); err != nil {
+			return nil, err
+		}
+
+		aclItems, err := parseAcl(acl.String)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, schemaAclObject{
+			description: "table " + schemaName + "." + tableName,
+			on:          quoteIdentifier(schemaName) + "." + quoteIdentifier(tableName),
+			owner:       owner.String,
+			acl:         aclItems,
+			expected:    func(g schemaPrivilegeGrant) string { return g.tablePrivileges },
+		})
+	}
+	return objects, rows.Err()
+}
+
+// Default privileges of every user in the schema. The declared table privileges are granted as default
+// privileges of the provider user, other users may only keep a subset of them
+func readSchemaDefaultAclObjects(q Queryer, schemaOid int, schemaName string) ([]schemaAclObject, error) {
+
+	var currentUser string
+	if err := q.QueryRow("SELECT current_user").Scan(&currentUser); err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(`SELECT pu.usename, acl.defaclobjtype, array_to_string(acl.defaclacl, '|')
+		FROM pg_default_acl acl
+		JOIN pg_user pu ON pu.usesysid = acl.defacluser
+		WHERE acl.defaclnamespace = $1
+		ORDER BY pu.usename, acl.defaclobjtype`, schemaOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		objects            []schemaAclObject
+		currentUserDefines bool
+	)
+	for rows.Next() {
+		var (
+			owner      string
+			objectType string
+			acl        sql.NullString
+		)
+		if err := rows.Scan(&owner, &objectType, &acl); err != nil {
+			return nil, err
+		}
+
+		aclItems, err := parseAcl(acl.String)
+		if err != nil {
+			return nil, err
+		}
+
+		var object = newSchemaDefaultAclObject(owner, schemaName, defaultAclObjectTypes[objectType], aclItems)
+		if objectType != "r" {
+			object.expected = func(g schemaPrivilegeGrant) string { return "" }
+		}
+		if owner == currentUser && objectType == "r" {
+			currentUserDefines = true
+		} else {
+			object.revokeOnly = true
+		}
+		objects = append(objects, object)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !currentUserDefines {
+		objects = append(objects, newSchemaDefaultAclObject(currentUser, schemaName, defaultAclObjectTypes["r"], nil))
+	}
+	return objects, nil
+}
+
+func newSchemaDefaultAclObject(owner string, schemaName string, objectType string, acl []aclItem) schemaAclObject {
+	return schemaAclObject{
+		description: "default privileges for user " + owner + " on " + strings.ToLower(objectType) + " in schema " + schemaName,
+		prefix:      "ALTER DEFAULT PRIVILEGES FOR USER " + quoteIdentifier(owner) + " IN SCHEMA " + quoteIdentifier(schemaName) + " ",
+		on:          objectType,
+		owner:       owner,
+		acl:         acl,
+		expected:    func(g schemaPrivilegeGrant) string { return g.tablePrivileges },
+	}
+}
+
+func (o schemaAclObject) changes(grants []schemaPrivilegeGrant) []schemaPrivilegeChange {
+
+	var changes []schemaPrivilegeChange
+
+	for _, item := range o.acl {
+		if item.isGrantedTo(granteeTypeUser, o.owner) {
+			continue
+		}
+
+		var expected string
+		if grant, ok := findSchemaPrivilegeGrant(grants, item); ok {
+			expected = o.expected(grant)
+		}
+
+		var extra, _ = diffAclPrivileges(item.privileges, expected)
+		if extra != "" {
+			changes = append(changes, schemaPrivilegeChange{
+				grantee:     item,
+				description: o.description + ": revoke " + aclPrivilegeList(extra) + " from " + item.granteeClause(),
+				statement:   o.prefix + "REVOKE " + aclPrivilegeList(extra) + " ON " + o.on + " FROM " + quotedGranteeClause(item),
+			})
+		}
+
+		//Revoking a privilege revokes its grant option too, the privileges that are kept only lose the option
+		if options, _ := diffAclPrivileges(aclGrantOptions(item.privileges), extra); options != "" {
+			changes = append(changes, schemaPrivilegeChange{
+				grantee:     item,
+				description: o.description + ": revoke grant option for " + aclPrivilegeList(options) + " from " + item.granteeClause(),
+				statement:   o.prefix + "REVOKE GRANT OPTION FOR " + aclPrivilegeList(options) + " ON " + o.on + " FROM " + quotedGranteeClause(item),
+			})
+		}
+	}
+
+	if o.revokeOnly {
+		return changes
+	}
+
+	for _, grant := range grants {
+		if grant.grantee.isGrantedTo(granteeTypeUser, o.owner) {
+			continue
+		}
+
+		var granted string
+		for _, item := range o.acl {
+			if item.isGrantedTo(grant.grantee.granteeType, grant.grantee.grantee) {
+				granted = item.privileges
+				break
+			}
+		}
+
+		if _, missing := diffAclPrivileges(granted, o.expected(grant)); missing != "" {
+			changes = append(changes, schemaPrivilegeChange{
+				grantee:     grant.grantee,
+				description: o.description + ": grant " + aclPrivilegeList(missing) + " to " + grant.grantee.granteeClause(),
+				statement:   o.prefix + "GRANT " + aclPrivilegeList(missing) + " ON " + o.on + " TO " + quotedGranteeClause(grant.grantee),
+			})
+		}
+	}
+
+	return changes
+}
+
+func findSchemaPrivilegeGrant(grants []schemaPrivilegeGrant, item aclItem) (schemaPrivilegeGrant, bool) {
+	for _, grant := range grants {
+		if item.isGrantedTo(grant.grantee.granteeType, grant.grantee.grantee) {
+			return grant, true
+		}
+	}
+	return schemaPrivilegeGrant{}, false
+}
+
+// RULE and TRIGGER are stored in acls, eg by GRANT ALL on a table, but can't be granted or revoked on their own
+const ungrantableAclPrivileges = "Rt"
+
+// The privileges granted but not expected, and expected but not granted, as acl letters.
+// Grant options (*), unknown letters and ungrantableAclPrivileges are ignored, see aclGrantOptions
+func diffAclPrivileges(granted string, expected string) (string, string) {
+	var extra, missing strings.Builder
+	for _, p := range granted {
+		if _, ok := aclPrivileges[p]; ok && !strings.ContainsRune(ungrantableAclPrivileges, p) && !strings.ContainsRune(expected, p) {
+			extra.WriteRune(p)
+		}
+	}
+	for _, p := range expected {
+		if !strings.ContainsRune(granted, p) {
+			missing.WriteRune(p)
+		}
+	}
+	return extra.String(), missing.String()
+}
+
+// The privileges that were granted with grant option, eg r for r*a
+func aclGrantOptions(privileges string) string {
+	var letters strings.Builder
+	var runes = []rune(privileges)
+	for i, p := range runes {
+		if _, ok := aclPrivileges[p]; ok && !strings.ContainsRune(ungrantableAclPrivileges, p) && i+1 < len(runes) && runes[i+1] == '*' {
+			letters.WriteRune(p)
+		}
+	}
+	return letters.String()
+}
+
+// Eg SELECT, INSERT for ra
+func aclPrivilegeList(letters string) string {
+	return strings.Join(aclItem{privileges: letters}.privilegeNames(), ", ")
+}
+
+// Acl letters for privilege names, eg ra for SELECT and INSERT
+func aclPrivilegeLetters(names []interface{}) string {
+	var letters strings.Builder
+	for _, name := range names {
+		for letter, privilege := range aclPrivileges {
+			if name.(string) == privilege {
+				letters.WriteRune(letter)
+			}
+		}
+	}
+	return letters.String()
+}
+
+// Like granteeClause, with the name quoted for use in statements
+func quotedGranteeClause(item aclItem) string {
+	switch item.granteeType {
+	case granteeTypeGroup:
+		return "GROUP " + quoteIdentifier(item.grantee)
+	case granteeTypeRole:
+		return "ROLE " + quoteIdentifier(item.grantee)
+	case granteeTypePublic:
+		return "PUBLIC"
+	default:
+		return quoteIdentifier(item.grantee)
+	}
+}
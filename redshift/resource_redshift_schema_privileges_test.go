@@ -0,0 +1,166 @@
+package redshift
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffAclPrivileges(t *testing.T) {
+	cases := []struct {
+		granted, expected string
+		extra, missing    string
+	}{
+		{"", "", "", ""},
+		{"r", "r", "", ""},
+		{"ra", "r", "a", ""},
+		{"r", "ra", "", "a"},
+		{"r*a*", "r", "a", ""},
+		{"rw", "ad", "rw", "ad"},
+		{"arwdRxt", "r", "awdx", ""},
+		{"arwdRxt", "arwdx", "", ""},
+		{"", "UC", "", "UC"},
+	}
+
+	for _, c := range cases {
+		extra, missing := diffAclPrivileges(c.granted, c.expected)
+		if extra != c.extra || missing != c.missing {
+			t.Errorf("diffAclPrivileges(%q, %q): expected %q, %q, got %q, %q", c.granted, c.expected, c.extra, c.missing, extra, missing)
+		}
+	}
+}
+
+func TestAclGrantOptions(t *testing.T) {
+	cases := map[string]string{
+		"":      "",
+		"ra":    "",
+		"r*a":   "r",
+		"r*a*w": "ra",
+		"*":     "",
+		"R*t*r": "",
+	}
+
+	for privileges, expected := range cases {
+		if options := aclGrantOptions(privileges); options != expected {
+			t.Errorf("aclGrantOptions(%q): expected %q, got %q", privileges, expected, options)
+		}
+	}
+}
+
+func TestAclPrivilegeLetters(t *testing.T) {
+	cases := []struct {
+		names    []interface{}
+		expected string
+	}{
+		{nil, ""},
+		{[]interface{}{"SELECT"}, "r"},
+		{[]interface{}{"SELECT", "INSERT", "DROP"}, "raD"},
+		{[]interface{}{"USAGE", "CREATE"}, "UC"},
+		{[]interface{}{"UNKNOWN"}, ""},
+	}
+
+	for _, c := range cases {
+		if letters := aclPrivilegeLetters(c.names); letters != c.expected {
+			t.Errorf("aclPrivilegeLetters(%v): expected %q, got %q", c.names, c.expected, letters)
+		}
+	}
+}
+
+func TestSchemaAclObjectChanges(t *testing.T) {
+	var readers = schemaPrivilegeGrant{
+		grantee:         aclItem{granteeType: granteeTypeGroup, grantee: "readers"},
+		tablePrivileges: "r",
+	}
+	var loader = schemaPrivilegeGrant{
+		grantee:         aclItem{granteeType: granteeTypeUser, grantee: "loader"},
+		tablePrivileges: "ra",
+	}
+
+	var table = func(acl string) schemaAclObject {
+		items, err := parseAcl(acl)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return schemaAclObject{
+			description: "table etl.orders",
+			on:          `"etl"."orders"`,
+			owner:       "owner",
+			acl:         items,
+			expected:    func(g schemaPrivilegeGrant) string { return g.tablePrivileges },
+		}
+	}
+
+	cases := []struct {
+		name       string
+		object     schemaAclObject
+		grants     []schemaPrivilegeGrant
+		statements []string
+	}{
+		{
+			name:   "compliant",
+			object: table("owner=arwdRxt/owner|group readers=r/owner|loader=ra/owner"),
+			grants: []schemaPrivilegeGrant{readers, loader},
+		},
+		{
+			name:   "owner is left alone",
+			object: table("owner=arwdRxt/owner"),
+			grants: []schemaPrivilegeGrant{{grantee: aclItem{granteeType: granteeTypeUser, grantee: "owner"}}},
+		},
+		{
+			name:   "missing grants",
+			object: table("owner=arwdRxt/owner|loader=r/owner"),
+			grants: []schemaPrivilegeGrant{readers, loader},
+			statements: []string{
+				`GRANT SELECT ON "etl"."orders" TO GROUP "readers"`,
+				`GRANT INSERT ON "etl"."orders" TO "loader"`,
+			},
+		},
+		{
+			name:   "undeclared grantees and extra privileges are revoked",
+			object: table("owner=arwdRxt/owner|group readers=rw/owner|=r/owner|other=d/owner"),
+			grants: []schemaPrivilegeGrant{readers},
+			statements: []string{
+				`REVOKE UPDATE ON "etl"."orders" FROM GROUP "readers"`,
+				`REVOKE SELECT ON "etl"."orders" FROM PUBLIC`,
+				`REVOKE DELETE ON "etl"."orders" FROM "other"`,
+			},
+		},
+		{
+			name:   "rule and trigger from grant all are left alone",
+			object: table("owner=arwdRxt/owner|x=arwdRxt/owner|group readers=arwdRxt/owner"),
+			grants: []schemaPrivilegeGrant{readers},
+			statements: []string{
+				`REVOKE INSERT, SELECT, UPDATE, DELETE, REFERENCES ON "etl"."orders" FROM "x"`,
+				`REVOKE INSERT, UPDATE, DELETE, REFERENCES ON "etl"."orders" FROM GROUP "readers"`,
+			},
+		},
+		{
+			name:   "grant options are revoked",
+			object: table("owner=arwdRxt/owner|loader=r*a*w*/owner"),
+			grants: []schemaPrivilegeGrant{loader},
+			statements: []string{
+				`REVOKE UPDATE ON "etl"."orders" FROM "loader"`,
+				`REVOKE GRANT OPTION FOR SELECT, INSERT ON "etl"."orders" FROM "loader"`,
+			},
+		},
+		{
+			name: "revoke only objects don't grant",
+			object: func() schemaAclObject {
+				var o = table("owner=arwdRxt/owner|group readers=rw/owner")
+				o.revokeOnly = true
+				return o
+			}(),
+			grants:     []schemaPrivilegeGrant{readers, loader},
+			statements: []string{`REVOKE UPDATE ON "etl"."orders" FROM GROUP "readers"`},
+		},
+	}
+
+	for _, c := range cases {
+		var statements []string
+		for _, change := range c.object.changes(c.grants) {
+			statements = append(statements, change.statement)
+		}
+		if !reflect.DeepEqual(statements, c.statements) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.statements, statements)
+		}
+	}
+}
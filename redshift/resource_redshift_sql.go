@@ -0,0 +1,236 @@
+package redshift
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+/*
+Escape hatch for objects the provider doesn't manage yet. create_sql, update_sql and destroy_sql are run as they are,
+each in its own transaction, so they can hold several statements separated by ;
+Without update_sql, any change to create_sql recreates the resource: destroy_sql is run, then create_sql.
+
+read_sql is an optional SELECT whose result is hashed. The hash taken after an apply is kept in
+applied_read_result_hash, and every refresh stores the current one in read_result_hash. When they differ the object has
+drifted, and the next apply runs update_sql, or recreates the resource if there is none.
+The sql attributes are sensitive and never logged, as statements like CREATE EXTERNAL SCHEMA may hold credentials.
+*/
+func redshiftSql() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceRedshiftSqlCreate,
+		Read:          resourceRedshiftSqlRead,
+		Update:        resourceRedshiftSqlUpdate,
+		Delete:        resourceRedshiftSqlDelete,
+		CustomizeDiff: resourceRedshiftSqlCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"create_sql": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			"destroy_sql": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			"update_sql": { //Run instead of recreating when create_sql changes or drift is detected
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"read_sql": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateReadOnlyQuery,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"read_result_hash": { //Hash of the read_sql result at the last refresh
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"applied_read_result_hash": { //Hash of the read_sql result at the last apply
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceRedshiftSqlCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	if err := execSqlInTransaction(redshiftClient, d.Get("create_sql").(string)); err != nil {
+		return fmt.Errorf("Could not run create_sql: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(hashcode.String(d.Get("database").(string) + d.Get("create_sql").(string))))
+
+	return readRedshiftSqlAfterApply(d, redshiftClient)
+}
+
+func resourceRedshiftSqlRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	hash, err := readSqlResultHash(redshiftClient, d.Get("read_sql").(string))
+	if err != nil {
+		return err
+	}
+
+	d.Set("read_result_hash", hash)
+
+	return nil
+}
+
+func resourceRedshiftSqlUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	//Both hashes are still the values from the last refresh here
+	appliedHash, _ := d.GetChange("applied_read_result_hash")
+	var drifted = d.Get("read_sql").(string) != "" && d.Get("read_result_hash").(string) != appliedHash.(string)
+
+	if d.HasChange("create_sql") || drifted {
+		if err := execSqlInTransaction(redshiftClient, d.Get("update_sql").(string)); err != nil {
+			return fmt.Errorf("Could not run update_sql: %s", err)
+		}
+	}
+
+	return readRedshiftSqlAfterApply(d, redshiftClient)
+}
+
+func resourceRedshiftSqlDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	if err := execSqlInTransaction(redshiftClient, d.Get("destroy_sql").(string)); err != nil {
+		return fmt.Errorf("Could not run destroy_sql: %s", err)
+	}
+
+	return nil
+}
+
+func resourceRedshiftSqlCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	var canUpdate = d.Get("update_sql").(string) != ""
+
+	if d.HasChange("create_sql") && !canUpdate {
+		if err := d.ForceNew("create_sql"); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("read_sql").(string) != "" && d.Get("read_result_hash").(string) != d.Get("applied_read_result_hash").(string) {
+		log.Printf("Result of read_sql has drifted for redshift_sql %s", d.Id())
+
+		if err := d.SetNewComputed("applied_read_result_hash"); err != nil {
+			return err
+		}
+		if !canUpdate {
+			if err := d.ForceNew("applied_read_result_hash"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// After create_sql or update_sql has run, the current read_sql result is what is expected from now on
+func readRedshiftSqlAfterApply(d *schema.ResourceData, db *sql.DB) error {
+
+	hash, err := readSqlResultHash(db, d.Get("read_sql").(string))
+	if err != nil {
+		return err
+	}
+
+	d.Set("read_result_hash", hash)
+	d.Set("applied_read_result_hash", hash)
+
+	return nil
+}
+
+func execSqlInTransaction(db *sql.DB, statements string) error {
+
+	if strings.TrimSpace(statements) == "" {
+		return nil
+	}
+
+	tx, txErr := db.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	//The statements aren't logged, they may hold credentials
+	if _, err := tx.Exec(statements); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Returns an empty hash if there is no read_sql
+func readSqlResultHash(db *sql.DB, query string) (string, error) {
+
+	if query == "" {
+		return "", nil
+	}
+
+	tx, txErr := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if txErr != nil {
+		return "", fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+	//Nothing to commit
+	defer tx.Rollback()
+
+	columns, rows, err := queryRowsAsStrings(tx, query)
+	if err != nil {
+		return "", fmt.Errorf("Could not run read_sql: %s", err)
+	}
+
+	var hash = sha256.New()
+	fmt.Fprintf(hash, "%q\n", columns)
+	for _, row := range rows {
+		for _, value := range row {
+			if value.Valid {
+				fmt.Fprintf(hash, "%q,", value.String)
+			} else {
+				fmt.Fprint(hash, "NULL,")
+			}
+		}
+		fmt.Fprint(hash, "\n")
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,56 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestReadSqlResultHash(t *testing.T) {
+	var hash = func(rows [][]driver.Value) string {
+		var db = newFakeDb(fakeResult{match: "FROM pg_user", rows: rows})
+		defer db.Close()
+
+		hash, err := readSqlResultHash(db, "SELECT usename, useconfig FROM pg_user")
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return hash
+	}
+
+	var rows = [][]driver.Value{{"etl", "statement_timeout=60000"}, {"loader", nil}}
+
+	if hash(rows) != hash(rows) {
+		t.Errorf("expected the same result to hash the same")
+	}
+	if hash(rows) == hash([][]driver.Value{{"etl", "statement_timeout=60000"}, {"loader", "NULL"}}) {
+		t.Errorf("expected NULL and 'NULL' to hash differently")
+	}
+	if hash(rows) == hash(rows[:1]) {
+		t.Errorf("expected a missing row to change the hash")
+	}
+
+	if hash, err := readSqlResultHash(nil, ""); hash != "" || err != nil {
+		t.Errorf("expected an empty hash without read_sql, got %s, %v", hash, err)
+	}
+}
+
+func TestExecSqlInTransaction(t *testing.T) {
+	cases := map[string][]string{
+		"":     {},
+		"  \n": {},
+		"CREATE TABLE t (id int); GRANT SELECT ON t TO etl": {"CREATE TABLE t (id int); GRANT SELECT ON t TO etl"},
+	}
+
+	for statements, expected := range cases {
+		var db, executed = newRecordingFakeDb()
+
+		if err := execSqlInTransaction(db, statements); err != nil {
+			t.Errorf("%q: err: %s", statements, err)
+		}
+		if !reflect.DeepEqual(*executed, expected) {
+			t.Errorf("%q: expected %v, got %v", statements, expected, *executed)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,156 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_TABLE.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_SVV_RLS_RELATION.html
+
+/*
+Turns row level security on for a table, deleting the resource turns it off again. While it is on, users only see
+the rows allowed by the policies attached to the table for them or their roles, and nothing if there are none.
+The id is schema.table.
+*/
+func redshiftTableRls() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceRedshiftTableRlsCreate,
+		Read:   resourceRedshiftTableRlsRead,
+		Update: resourceRedshiftTableRlsUpdate,
+		Delete: resourceRedshiftTableRlsDelete,
+
+		Schema: map[string]*schema.Schema{
+			"schema": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"table": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"conjunction_type": { //How several policies attached for the same user are combined, AND or OR
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "AND",
+				ValidateFunc: validation.StringInSlice([]string{"AND", "OR"}, true),
+				StateFunc:    upperCaseStateFunc,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceRedshiftTableRlsCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	if err := alterTableRls(redshiftClient, d, true); err != nil {
+		return err
+	}
+
+	d.SetId(d.Get("schema").(string) + "." + d.Get("table").(string))
+
+	return readRedshiftTableRls(d, redshiftClient)
+}
+
+func resourceRedshiftTableRlsRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	return readRedshiftTableRls(d, redshiftClient)
+}
+
+func readRedshiftTableRls(d *schema.ResourceData, q Queryer) error {
+
+	var (
+		rlsOn           bool
+		conjunctionType sql.NullString
+	)
+
+	err := q.QueryRow(`SELECT is_rls_on, rls_conjunction_type FROM svv_rls_relation
+		WHERE datname = current_database() AND relschema = $1 AND relname = $2`,
+		d.Get("schema").(string), d.Get("table").(string)).Scan(&rlsOn, &conjunctionType)
+	if err == sql.ErrNoRows {
+		log.Printf("Table %s no longer exists", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	//If RLS was turned off outside terraform, the next apply turns it on again
+	if !rlsOn {
+		log.Printf("Row level security has been turned off for %s", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if conjunctionType.Valid && conjunctionType.String != "" {
+		d.Set("conjunction_type", strings.ToUpper(conjunctionType.String))
+	}
+
+	return nil
+}
+
+func resourceRedshiftTableRlsUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	if d.HasChange("conjunction_type") {
+		if err := alterTableRls(redshiftClient, d, true); err != nil {
+			return err
+		}
+	}
+
+	return readRedshiftTableRls(d, redshiftClient)
+}
+
+func resourceRedshiftTableRlsDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	return alterTableRls(redshiftClient, d, false)
+}
+
+func alterTableRls(db *sql.DB, d *schema.ResourceData, on bool) error {
+
+	var alterStatement = "ALTER TABLE " + qualifiedTableName(d) + " ROW LEVEL SECURITY OFF"
+	if on {
+		alterStatement = "ALTER TABLE " + qualifiedTableName(d) + " ROW LEVEL SECURITY ON CONJUNCTION TYPE " + d.Get("conjunction_type").(string)
+	}
+
+	log.Print("Alter table statement: " + alterStatement)
+
+	if _, err := db.Exec(alterStatement); err != nil {
+		return fmt.Errorf("Could not alter row level security of %s: %s", qualifiedTableName(d), err)
+	}
+	return nil
+}
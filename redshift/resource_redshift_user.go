@@ -1,13 +1,17 @@
 package redshift
 
 import (
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"log"
+	"math/big"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/lib/pq"
 )
 
 func redshiftUser() *schema.Resource {
@@ -20,6 +24,7 @@ func redshiftUser() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceRedshiftUserImport,
 		},
+		CustomizeDiff: resourceRedshiftUserCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"username": { //This isn't immutable. The usesysid returned should be used as the id
@@ -35,6 +40,30 @@ func redshiftUser() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"generate_password": { //The provider generates the password and exposes it as generated_password
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"password", "password_disabled"},
+			},
+			"generated_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"rotation_days": { //Only used with generate_password. 0 means the password is never rotated on a schedule
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"rotation_trigger": { //Only used with generate_password. Any change to this value rotates the password
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"password_rotated_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"password_disabled": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -55,11 +84,30 @@ func redshiftUser() *schema.Resource {
 				Optional: true,
 				Default:  "RESTRICTED",
 			},
+			"session_timeout": { //Seconds a session can be idle or inactive, 0 means the cluster default is used
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
 			"superuser": { //If true set CREATEUSER
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
 			},
+			"parameters": { //Session defaults set with ALTER USER ... SET, eg search_path, query_group, statement_timeout, timezone
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"reassign_owned_to": { //usesysid of the user that gets the objects owned by this user when it is deleted. Defaults to the provider user
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"fail_if_owns_objects": { //If true deleting the user fails while it still owns objects, rather than transferring them
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"usesysid": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -96,8 +144,19 @@ func resourceRedshiftUserCreate(d *schema.ResourceData, meta interface{}) error
 
 	var createStatement string = "create user " + d.Get("username").(string) + " with password "
 
+	//Only stored in state once the user has been created
+	var generatedPassword string
+
 	if v, ok := d.GetOk("password_disabled"); ok && v.(bool) {
 		createStatement += " DISABLE "
+	} else if v, ok := d.GetOk("generate_password"); ok && v.(bool) {
+		password, err := generatePassword(32)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Could not generate password: %s", err)
+		}
+		generatedPassword = password
+		createStatement += "'" + generatedPassword + "' "
 	} else if v, ok := d.GetOk("password"); ok {
 		createStatement += "'" + v.(string) + "' "
 	} else {
@@ -128,6 +187,9 @@ func resourceRedshiftUserCreate(d *schema.ResourceData, meta interface{}) error
 			panic(v.(string))
 		}
 	}
+	if v, ok := d.GetOk("session_timeout"); ok && v.(int) > 0 {
+		createStatement += fmt.Sprintf(" SESSION TIMEOUT %d ", v.(int))
+	}
 	if v, ok := d.GetOk("superuser"); ok && v.(bool) {
 		createStatement += " CREATEUSER "
 	}
@@ -154,6 +216,15 @@ func resourceRedshiftUserCreate(d *schema.ResourceData, meta interface{}) error
 
 	d.SetId(usesysid)
 
+	if v, ok := d.GetOk("parameters"); ok {
+		for parameter, value := range v.(map[string]interface{}) {
+			if err := setUserParameter(tx, d.Get("username").(string), parameter, value.(string)); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
 	readErr := readRedshiftUser(d, tx)
 
 	if readErr != nil {
@@ -161,7 +232,13 @@ func resourceRedshiftUserCreate(d *schema.ResourceData, meta interface{}) error
 		return readErr
 	}
 
-	tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if generatedPassword != "" {
+		setGeneratedPassword(d, generatedPassword)
+	}
 	return nil
 }
 
@@ -194,14 +271,15 @@ func readRedshiftUser(d *schema.ResourceData, tx *sql.Tx) error {
 		usesuper     bool
 		valuntil     sql.NullString
 		useconnlimit sql.NullString
+		useconfig    pq.StringArray
 	)
 
-	var readUserQuery = "select usename, usecreatedb, usesuper, valuntil, useconnlimit " +
+	var readUserQuery = "select usename, usecreatedb, usesuper, valuntil, useconnlimit, useconfig " +
 		"from pg_user_info where usesysid = $1"
 
 	log.Print("Reading redshift user with query: " + readUserQuery)
 
-	err := tx.QueryRow(readUserQuery, d.Id()).Scan(&usename, &usecreatedb, &usesuper, &valuntil, &useconnlimit)
+	err := tx.QueryRow(readUserQuery, d.Id()).Scan(&usename, &usecreatedb, &usesuper, &valuntil, &useconnlimit, &useconfig)
 
 	if err != nil {
 		log.Print("Reading user does not exist")
@@ -229,6 +307,37 @@ func readRedshiftUser(d *schema.ResourceData, tx *sql.Tx) error {
 		d.Set("connection_limit", nil)
 	}
 
+	d.Set("parameters", parseUserConfig(useconfig))
+
+	var (
+		syslogAccess   sql.NullString
+		sessionTimeout sql.NullInt64
+	)
+
+	//pg_user_info doesn't expose these
+	err = tx.QueryRow("select syslog_access, session_timeout from svv_user_info where user_id = $1", d.Id()).Scan(&syslogAccess, &sessionTimeout)
+
+	if err != nil {
+		log.Print("Could not read syslog access and session timeout from svv_user_info")
+		log.Print(err)
+		return err
+	}
+
+	//Superusers always have unrestricted access, whatever was configured
+	if !usesuper {
+		if syslogAccess.Valid {
+			d.Set("syslog_access", syslogAccess.String)
+		} else {
+			d.Set("syslog_access", "RESTRICTED")
+		}
+	}
+
+	if sessionTimeout.Valid {
+		d.Set("session_timeout", int(sessionTimeout.Int64))
+	} else {
+		d.Set("session_timeout", 0)
+	}
+
 	return nil
 }
 
@@ -241,6 +350,20 @@ func resourceRedshiftUserUpdate(d *schema.ResourceData, meta interface{}) error
 		panic(txErr)
 	}
 
+	//A rotated password is only stored in state once it has been committed, state is saved even if Update fails
+	var (
+		password = userPassword(d)
+		rotated  = generatedPasswordNeedsRotation(d)
+	)
+	if rotated {
+		generatedPassword, err := generatePassword(32)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Could not generate password: %s", err)
+		}
+		password = generatedPassword
+	}
+
 	if d.HasChange("username") {
 
 		oldUsername, newUsername := d.GetChange("username")
@@ -251,11 +374,11 @@ func resourceRedshiftUserUpdate(d *schema.ResourceData, meta interface{}) error
 		}
 
 		//If name changes we also need to reset the password
-		if err := resetPassword(tx, d, newUsername.(string)); err != nil {
+		if err := resetPassword(tx, d, newUsername.(string), password); err != nil {
 			return err
 		}
-	} else if d.HasChange("password") || d.HasChange("password_disabled") || d.HasChange("valid_until") {
-		if err := resetPassword(tx, d, d.Get("username").(string)); err != nil {
+	} else if d.HasChange("password") || d.HasChange("password_disabled") || d.HasChange("valid_until") || rotated {
+		if err := resetPassword(tx, d, d.Get("username").(string), password); err != nil {
 			return err
 		}
 	}
@@ -283,6 +406,21 @@ func resourceRedshiftUserUpdate(d *schema.ResourceData, meta interface{}) error
 			return err
 		}
 	}
+	if d.HasChange("session_timeout") {
+		var sessionTimeoutStatement = "alter user " + d.Get("username").(string) + " RESET SESSION TIMEOUT"
+		if v := d.Get("session_timeout").(int); v > 0 {
+			sessionTimeoutStatement = fmt.Sprintf("alter user %s SESSION TIMEOUT %d", d.Get("username").(string), v)
+		}
+		if _, err := tx.Exec(sessionTimeoutStatement); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("parameters") {
+		if err := updateUserParameters(tx, d); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
 	if d.HasChange("superuser") {
 		if v, ok := d.GetOk("superuser"); ok && v.(bool) {
 			if _, err := tx.Exec("alter user " + d.Get("username").(string) + " CREATEUSER "); err != nil {
@@ -302,11 +440,17 @@ func resourceRedshiftUserUpdate(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
-	tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if rotated {
+		setGeneratedPassword(d, password)
+	}
 	return nil
 }
 
-func resetPassword(tx *sql.Tx, d *schema.ResourceData, username string) error {
+func resetPassword(tx *sql.Tx, d *schema.ResourceData, username string, password string) error {
 
 	if v, ok := d.GetOk("password_disabled"); ok && v.(bool) {
 
@@ -318,7 +462,7 @@ func resetPassword(tx *sql.Tx, d *schema.ResourceData, username string) error {
 		return nil
 
 	} else {
-		var resetPasswordQuery = "alter user " + username + " password '" + d.Get("password").(string) + "' "
+		var resetPasswordQuery = "alter user " + username + " password '" + password + "' "
 		if v, ok := d.GetOk("valid_until"); ok {
 			resetPasswordQuery += " VALID UNTIL '" + v.(string) + "'"
 
@@ -330,142 +474,293 @@ func resetPassword(tx *sql.Tx, d *schema.ResourceData, username string) error {
 	}
 }
 
-func resourceRedshiftUserDelete(d *schema.ResourceData, meta interface{}) error {
+// The password that should be set for the user, either configured or generated by the provider
+func userPassword(d *schema.ResourceData) string {
+	if v, ok := d.GetOk("generate_password"); ok && v.(bool) {
+		return d.Get("generated_password").(string)
+	}
+	return d.Get("password").(string)
+}
 
-	redshiftClient := meta.(*Client).db
-	redshiftClientConfig := meta.(*Client).config
+func resourceRedshiftUserCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	// On create the generated password is unknown anyway
+	if d.Id() == "" || !d.Get("generate_password").(bool) {
+		return nil
+	}
 
-	tx, txErr := redshiftClient.Begin()
+	if d.HasChange("generate_password") || d.HasChange("rotation_trigger") ||
+		passwordRotationDue(d.Get("password_rotated_at").(string), d.Get("rotation_days").(int)) {
 
-	if txErr != nil {
-		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
-	}
-
-	// https://docs.aws.amazon.com/redshift/latest/dg/r_DROP_USER.html
-	// If a user owns an object, first drop the object or change its ownership to another user before dropping
-	// the original user. If the user has privileges for an object, first revoke the privileges before dropping
-	// the user. The following example shows dropping an object, changing ownership, and revoking privileges
-	// before dropping the user
-	//
-	// There is no equivalent of Postgres REASSIGN USER unfortunately
-	//
-	// It is necessary to query and reassign to current user.
-	// See some discussion her: https://dba.stackexchange.com/questions/143938/drop-user-in-redshift-which-has-privilege-on-some-object
-	//
-	// Derived from https://github.com/awslabs/amazon-redshift-utils/blob/master/src/AdminViews/v_find_dropuser_objs.sql
-	var reassignOwnerGenerator = `SELECT owner.ddl
-		FROM (
-				-- Functions owned by the user
-				SELECT pgu.usesysid,
-				'alter function ' || QUOTE_IDENT(nc.nspname) || '.' ||textin (regprocedureout (pproc.oid::regprocedure)) || ' owner to '
-				FROM pg_proc pproc,pg_user pgu,pg_namespace nc
-				WHERE pproc.pronamespace = nc.oid
-				AND   pproc.proowner = pgu.usesysid
-			UNION ALL
-				-- Databases owned by the user
-				SELECT pgu.usesysid,
-				'alter database ' || QUOTE_IDENT(pgd.datname) || ' owner to '
-				FROM pg_database pgd,
-				     pg_user pgu
-				WHERE pgd.datdba = pgu.usesysid
-			UNION ALL
-				-- Schemas owned by the user
-				SELECT pgu.usesysid,
-				'alter schema '|| QUOTE_IDENT(pgn.nspname) ||' owner to '
-				FROM pg_namespace pgn,
-				     pg_user pgu
-				WHERE pgn.nspowner = pgu.usesysid
-			UNION ALL
-				-- Tables or Views owned by the user
-				SELECT pgu.usesysid,
-				'alter table ' || QUOTE_IDENT(nc.nspname) || '.' || QUOTE_IDENT(pgc.relname) || ' owner to '
-				FROM pg_class pgc,
-				     pg_user pgu,
-				     pg_namespace nc
-				WHERE pgc.relnamespace = nc.oid
-				AND   pgc.relkind IN ('r','v')
-				AND   pgu.usesysid = pgc.relowner
-				AND   nc.nspname NOT ILIKE 'pg\_temp\_%'
-		)
-		OWNER("userid", "ddl")
-		WHERE owner.userid = $1;`
-
-	rows, reassignOwnerStatementErr := tx.Query(reassignOwnerGenerator, d.Id())
+		if err := d.SetNewComputed("generated_password"); err != nil {
+			return err
+		}
+		if err := d.SetNewComputed("password_rotated_at"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	defer rows.Close()
+func generatedPasswordNeedsRotation(d *schema.ResourceData) bool {
+	if v, ok := d.GetOk("generate_password"); !ok || !v.(bool) {
+		return false
+	}
 
-	if reassignOwnerStatementErr != nil {
-		tx.Rollback()
-		return reassignOwnerStatementErr
+	//The new value is unknown at this point if the rotation was planned, so use the value from state
+	rotatedAt, _ := d.GetChange("password_rotated_at")
+
+	return d.HasChange("generate_password") || d.HasChange("rotation_trigger") ||
+		passwordRotationDue(rotatedAt.(string), d.Get("rotation_days").(int))
+}
+
+func passwordRotationDue(rotatedAt string, rotationDays int) bool {
+	if rotationDays <= 0 {
+		return false
+	}
+	if rotatedAt == "" {
+		return true
 	}
 
-	var reassignStatements []string
+	lastRotation, err := time.Parse(time.RFC3339, rotatedAt)
+	if err != nil {
+		log.Printf("Could not parse password_rotated_at %s, rotating password: %s", rotatedAt, err)
+		return true
+	}
 
-	for rows.Next() {
+	return !time.Now().Before(lastRotation.AddDate(0, 0, rotationDays))
+}
 
-		var reassignStatement string
+func setGeneratedPassword(d *schema.ResourceData, password string) {
+	d.Set("generated_password", password)
+	d.Set("password_rotated_at", time.Now().UTC().Format(time.RFC3339))
+}
 
-		err := rows.Scan(&reassignStatement)
-		if err != nil {
-			//Im not sure how this can happen
-			tx.Rollback()
-			return err
+// https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_USER.html
+// Passwords must be 8 to 64 characters and contain at least one uppercase letter, one lowercase letter and one number.
+// Only alphanumeric characters are used so the password never needs escaping.
+func generatePassword(length int) (string, error) {
+	const (
+		lower  = "abcdefghijklmnopqrstuvwxyz"
+		upper  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+		digits = "0123456789"
+	)
+	var charset = lower + upper + digits
+
+	for {
+		password := make([]byte, length)
+		for i := range password {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+			if err != nil {
+				return "", err
+			}
+			password[i] = charset[n.Int64()]
+		}
+
+		candidate := string(password)
+		if strings.ContainsAny(candidate, lower) && strings.ContainsAny(candidate, upper) && strings.ContainsAny(candidate, digits) {
+			return candidate, nil
 		}
-		reassignStatements = append(reassignStatements, reassignStatement)
 	}
+}
 
-	for _, statement := range reassignStatements {
-		_, err := tx.Exec(statement + redshiftClientConfig.user)
+// https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_USER.html
+// Parameters removed from config are RESET, new or changed ones are SET
+func updateUserParameters(tx *sql.Tx, d *schema.ResourceData) error {
+	var username = d.Get("username").(string)
 
-		if err != nil {
-			//Im not sure how this can happen
-			tx.Rollback()
+	oldParameters, newParameters := d.GetChange("parameters")
+
+	for parameter := range oldParameters.(map[string]interface{}) {
+		if _, ok := newParameters.(map[string]interface{})[parameter]; !ok {
+			if _, err := tx.Exec("alter user " + username + " reset " + parameter); err != nil {
+				return err
+			}
+		}
+	}
+
+	for parameter, value := range newParameters.(map[string]interface{}) {
+		if oldValue, ok := oldParameters.(map[string]interface{})[parameter]; ok && oldValue == value {
+			continue
+		}
+		if err := setUserParameter(tx, username, parameter, value.(string)); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	//We need to drop all privileges and default privileges
-	rows, schemasError := redshiftClient.Query("select nspname from pg_namespace")
-	defer rows.Close()
-	if schemasError != nil {
-		return schemasError
+func setUserParameter(tx *sql.Tx, username string, parameter string, value string) error {
+
+	var setParameterStatement = "alter user " + username + " set " + parameter + " to "
+
+	//search_path is a list, each schema has to be its own literal
+	if parameter == "search_path" {
+		var schemas []string
+		for _, s := range strings.Split(value, ",") {
+			schemas = append(schemas, quoteLiteral(strings.TrimSpace(s)))
+		}
+		setParameterStatement += strings.Join(schemas, ", ")
+	} else {
+		setParameterStatement += quoteLiteral(value)
 	}
 
-	for rows.Next() {
-		var schemaName string
-		err := rows.Scan(&schemaName)
-		if err != nil {
-			//Im not sure how this can happen
-			return err
+	log.Print("Set user parameter statement: " + setParameterStatement)
+
+	if _, err := tx.Exec(setParameterStatement); err != nil {
+		return fmt.Errorf("Could not set %s for redshift user %s: %s", parameter, username, err)
+	}
+	return nil
+}
+
+// useconfig is an array of parameter=value entries, eg {"search_path=\"$user\", public",query_group=etl}
+func parseUserConfig(useconfig []string) map[string]string {
+	var parameters = make(map[string]string)
+
+	for _, entry := range useconfig {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			log.Printf("Ignoring unexpected useconfig entry %s", entry)
+			continue
+		}
+
+		if parts[0] == "search_path" {
+			var schemas []string
+			for _, s := range strings.Split(parts[1], ",") {
+				schemas = append(schemas, strings.Trim(strings.TrimSpace(s), `"`))
+			}
+			parameters[parts[0]] = strings.Join(schemas, ", ")
+		} else {
+			parameters[parts[0]] = parts[1]
 		}
-		redshiftClient.Exec("REVOKE ALL ON ALL TABLES IN SCHEMA " + schemaName + " FROM " + d.Get("username").(string))
-		redshiftClient.Exec("ALTER DEFAULT PRIVILEGES IN SCHEMA " + schemaName + " REVOKE ALL ON TABLES FROM " + d.Get("username").(string) + " CASCADE")
 	}
+	return parameters
+}
 
-	_, dropUserErr := tx.Exec("DROP USER " + d.Get("username").(string))
+func quoteLiteral(literal string) string {
+	return "'" + strings.Replace(literal, "'", "''", -1) + "'"
+}
 
-	if dropUserErr != nil {
-		tx.Rollback()
-		return dropUserErr
+func resourceRedshiftUserDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient := meta.(*Client).db
+	redshiftClientConfig := meta.(*Client).config
+
+	usesysid, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
 	}
 
-	tx.Commit()
-	return nil
+	// Objects owned by the user are transferred to the provider user, unless another user is configured
+	var newOwner = redshiftClientConfig.user
+	if v, ok := d.GetOk("reassign_owned_to"); ok {
+		if newOwner, err = GetUsernameForUsesysid(redshiftClient, v.(int)); err != nil {
+			return fmt.Errorf("Could not find user %d to reassign owned objects to: %s", v.(int), err)
+		}
+	}
+
+	// The name as the acls hold it, which may differ in case from the config
+	username, err := GetUsernameForUsesysid(redshiftClient, usesysid)
+	if err != nil {
+		return fmt.Errorf("Could not find redshift user %d: %s", usesysid, err)
+	}
+
+	var plan = func(q Queryer, clusterWide bool) ([]cleanupStatement, error) {
+		reassignStatements, err := planReassignOwnedObjects(q, usesysid, newOwner, d.Get("fail_if_owns_objects").(bool), clusterWide)
+		if err != nil {
+			return nil, err
+		}
+		revokeStatements, err := planRevokeGrantedPrivileges(q, granteeTypeUser, username, clusterWide)
+		if err != nil {
+			return nil, err
+		}
+		return append(reassignStatements, revokeStatements...), nil
+	}
+
+	return dropWithCleanup(meta.(*Client), plan, "DROP USER "+quoteIdentifier(username))
 }
 
 func resourceRedshiftUserImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	usesysid, err := resolveImportId(meta.(*Client).db, d.Id(), "user",
+		"SELECT usesysid, usename FROM pg_user_info WHERE lower(usename) = lower($1)")
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(usesysid)
+
 	if err := resourceRedshiftUserRead(d, meta); err != nil {
 		return nil, err
 	}
 	return []*schema.ResourceData{d}, nil
 }
 
+func GetUsernameForUsesysid(q Queryer, usesysid int) (string, error) {
+
+	var name string
+
+	err := q.QueryRow("SELECT usename FROM pg_user_info WHERE usesysid = $1", usesysid).Scan(&name)
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// Objects that live in a database can be imported from another database than the provider's by prefixing the
+// import id with the database, eg analytics:etl. Returns an empty database otherwise
+func splitImportDatabase(importId string) (string, string) {
+	if parts := strings.SplitN(importId, ":", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return "", importId
+}
+
+// Import ids can be either the id (usesysid, grosysid, oid...) or the name of the object.
+// lookupQuery selects the id and name of objects whose name matches $1 ignoring case. An exact match wins,
+// otherwise the name is ambiguous if several objects only differ by case
+func resolveImportId(q Queryer, importId string, objectType string, lookupQuery string) (string, error) {
+
+	if _, err := strconv.Atoi(importId); err == nil {
+		return importId, nil
+	}
+
+	rows, err := q.Query(lookupQuery, importId)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var ids, matches []string
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return "", err
+		}
+		if name == importId {
+			return id, nil
+		}
+		ids = append(ids, id)
+		matches = append(matches, name)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("Could not import %s %s: no %s with that id or name exists", objectType, importId, objectType)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("Could not import %s %s: the name is ambiguous, it matches %s", objectType, importId, strings.Join(matches, ", "))
+	}
+}
+
 type Queryer interface {
 	Query(query string, args ...interface{}) (*sql.Rows, error)
 	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
-func GetUsersnamesForUsesysid(q Queryer, usersIdsInterface []interface{}) []string {
+// Users that don't exist are left out
+func GetUsersnamesForUsesysid(q Queryer, usersIdsInterface []interface{}) ([]string, error) {
 
 	var usersIds = make([]int, 0)
 
@@ -475,33 +770,29 @@ func GetUsersnamesForUsesysid(q Queryer, usersIdsInterface []interface{}) []stri
 
 	var usernames []string
 
+	if len(usersIds) == 0 {
+		return usernames, nil
+	}
+
 	//I couldnt figure out how to pass a slice to go sql
 	var selectUserQuery = fmt.Sprintf("select usename from pg_user_info where usesysid in (%s)", strings.Trim(strings.Join(strings.Fields(fmt.Sprint(usersIds)), ","), "[]"))
 
 	log.Print("Select user query: " + selectUserQuery)
 
 	rows, err := q.Query(selectUserQuery)
-
 	if err != nil {
-		// handle this error better than this
-		panic(err)
+		return nil, fmt.Errorf("Could not find redshift users %v: %s", usersIds, err)
 	}
 	defer rows.Close()
+
 	for rows.Next() {
 		var username string
-		err = rows.Scan(&username)
-		if err != nil {
-			// handle this error
-			panic(err)
+		if err := rows.Scan(&username); err != nil {
+			return nil, err
 		}
 
 		usernames = append(usernames, username)
 	}
-	// get any error encountered during iteration
-	err = rows.Err()
-	if err != nil {
-		panic(err)
-	}
 
-	return usernames
+	return usernames, rows.Err()
 }
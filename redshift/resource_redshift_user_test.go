@@ -0,0 +1,192 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	for _, length := range []int{8, 32, 64} {
+		for i := 0; i < 100; i++ {
+			password, err := generatePassword(length)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if len(password) != length {
+				t.Fatalf("expected %d characters, got %s", length, password)
+			}
+			if !strings.ContainsAny(password, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") ||
+				!strings.ContainsAny(password, "abcdefghijklmnopqrstuvwxyz") ||
+				!strings.ContainsAny(password, "0123456789") {
+				t.Fatalf("expected an uppercase letter, a lowercase letter and a digit, got %s", password)
+			}
+			if strings.ContainsAny(password, `'"\/@ `) {
+				t.Fatalf("expected no characters Redshift doesn't allow, got %s", password)
+			}
+		}
+	}
+}
+
+func TestPasswordRotationDue(t *testing.T) {
+	var format = func(tm time.Time) string { return tm.UTC().Format(time.RFC3339) }
+	var now = time.Now()
+
+	cases := []struct {
+		name         string
+		rotatedAt    string
+		rotationDays int
+		expected     bool
+	}{
+		{"rotation disabled", format(now.AddDate(0, 0, -100)), 0, false},
+		{"never rotated", "", 30, true},
+		{"unparseable", "yesterday", 30, true},
+		{"rotated just now", format(now), 30, false},
+		{"a minute before rotation_days", format(now.AddDate(0, 0, -30).Add(time.Minute)), 30, false},
+		{"exactly rotation_days ago", format(now.AddDate(0, 0, -30)), 30, true},
+		{"after rotation_days", format(now.AddDate(0, 0, -31)), 30, true},
+	}
+
+	for _, c := range cases {
+		if due := passwordRotationDue(c.rotatedAt, c.rotationDays); due != c.expected {
+			t.Errorf("%s: expected %t, got %t", c.name, c.expected, due)
+		}
+	}
+}
+
+func TestParseUserConfig(t *testing.T) {
+	cases := []struct {
+		useconfig []string
+		expected  map[string]string
+	}{
+		{nil, map[string]string{}},
+		{[]string{"query_group=etl"}, map[string]string{"query_group": "etl"}},
+		{[]string{`search_path="$user", public`, "statement_timeout=60000"},
+			map[string]string{"search_path": "$user, public", "statement_timeout": "60000"}},
+		{[]string{"datestyle=ISO, MDY"}, map[string]string{"datestyle": "ISO, MDY"}},
+		{[]string{"a=b=c", "invalid"}, map[string]string{"a": "b=c"}},
+	}
+
+	for _, c := range cases {
+		if parameters := parseUserConfig(c.useconfig); !reflect.DeepEqual(parameters, c.expected) {
+			t.Errorf("parseUserConfig(%v): expected %v, got %v", c.useconfig, c.expected, parameters)
+		}
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	cases := map[string]string{
+		"":        "''",
+		"etl":     "'etl'",
+		"o'brien": "'o''brien'",
+		"''":      "''''''",
+		"$user":   "'$user'",
+	}
+
+	for literal, expected := range cases {
+		if quoted := quoteLiteral(literal); quoted != expected {
+			t.Errorf("quoteLiteral(%s): expected %s, got %s", literal, expected, quoted)
+		}
+	}
+}
+
+func TestResolveImportId(t *testing.T) {
+	var lookup = "SELECT usesysid, usename FROM pg_user_info WHERE lower(usename) = lower($1)"
+
+	cases := []struct {
+		name     string
+		importId string
+		rows     [][]driver.Value
+		expected string
+		err      bool
+	}{
+		{"id", "100", nil, "100", false},
+		{"name", "etl", [][]driver.Value{{int64(100), "etl"}}, "100", false},
+		{"name in another case", "ETL", [][]driver.Value{{int64(100), "etl"}}, "100", false},
+		{"exact match wins", "Etl", [][]driver.Value{{int64(100), "etl"}, {int64(101), "Etl"}}, "101", false},
+		{"ambiguous", "ETL", [][]driver.Value{{int64(100), "etl"}, {int64(101), "Etl"}}, "", true},
+		{"missing", "etl", nil, "", true},
+	}
+
+	for _, c := range cases {
+		var db = newFakeDb(fakeResult{match: "pg_user_info", rows: c.rows})
+
+		id, err := resolveImportId(db, c.importId, "user", lookup)
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s", c.name, id)
+			}
+		} else if err != nil {
+			t.Errorf("%s: err: %s", c.name, err)
+		} else if id != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, id)
+		}
+		db.Close()
+	}
+}
+
+func TestSplitImportDatabase(t *testing.T) {
+	cases := []struct {
+		importId, database, id string
+	}{
+		{"etl", "", "etl"},
+		{"100", "", "100"},
+		{"analytics:etl", "analytics", "etl"},
+		{"analytics:100", "analytics", "100"},
+	}
+
+	for _, c := range cases {
+		if database, id := splitImportDatabase(c.importId); database != c.database || id != c.id {
+			t.Errorf("splitImportDatabase(%s): expected %s, %s, got %s, %s", c.importId, c.database, c.id, database, id)
+		}
+	}
+}
+
+func TestReadRedshiftUserSyslogAccessAndSessionTimeout(t *testing.T) {
+	cases := []struct {
+		name                   string
+		configured             string
+		superuser              bool
+		syslogAccess           driver.Value
+		sessionTimeout         driver.Value
+		expectedSyslogAccess   string
+		expectedSessionTimeout int
+	}{
+		{"null means restricted", "UNRESTRICTED", false, nil, nil, "RESTRICTED", 0},
+		{"read back", "RESTRICTED", false, "UNRESTRICTED", int64(600), "UNRESTRICTED", 600},
+		{"superusers keep the configured value", "RESTRICTED", true, "UNRESTRICTED", nil, "RESTRICTED", 0},
+	}
+
+	for _, c := range cases {
+		var db = newFakeDb(
+			fakeResult{match: "from pg_user_info", rows: [][]driver.Value{{"etl", false, c.superuser, nil, nil, nil}}},
+			fakeResult{match: "from svv_user_info", rows: [][]driver.Value{{c.syslogAccess, c.sessionTimeout}}},
+		)
+		var d = schema.TestResourceDataRaw(t, redshiftUser().Schema, map[string]interface{}{
+			"username":      "etl",
+			"syslog_access": c.configured,
+		})
+		d.SetId("100")
+
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := readRedshiftUser(d, tx); err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		tx.Rollback()
+		db.Close()
+
+		if syslogAccess := d.Get("syslog_access").(string); syslogAccess != c.expectedSyslogAccess {
+			t.Errorf("%s: expected syslog_access %s, got %s", c.name, c.expectedSyslogAccess, syslogAccess)
+		}
+		if sessionTimeout := d.Get("session_timeout").(int); sessionTimeout != c.expectedSessionTimeout {
+			t.Errorf("%s: expected session_timeout %d, got %d", c.name, c.expectedSessionTimeout, sessionTimeout)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package redshift
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+//https://docs.aws.amazon.com/redshift/latest/dg/r_CREATE_VIEW.html
+//https://docs.aws.amazon.com/redshift/latest/dg/materialized-view-create-sql-command.html
+//https://docs.aws.amazon.com/redshift/latest/dg/r_ALTER_MATERIALIZED_VIEW.html
+
+/*
+The id is the oid of the view. Redshift rewrites the query it stores in pg_views, so it can't be compared with the
+configured query. Instead a hash of the stored definition is kept after every apply, and if the definition changes
+outside terraform, the stored definition is put in query so the next plan puts the configured query back.
+Views and late binding views are replaced in place, materialized views have to be recreated to change their query.
+The owner of a materialized view can't be changed.
+*/
+func redshiftView() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceRedshiftViewCreate,
+		Read:          resourceRedshiftViewRead,
+		Update:        resourceRedshiftViewUpdate,
+		Delete:        resourceRedshiftViewDelete,
+		Exists:        resourceRedshiftViewExists,
+		CustomizeDiff: resourceRedshiftViewCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"schema": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				ValidateFunc: validateLowercaseName,
+				Required:     true,
+				ForceNew:     true,
+			},
+			"query": { //The SELECT the view is defined as
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressSurroundingWhitespaceDiff,
+			},
+			"type": { //view, late_binding (WITH NO SCHEMA BINDING) or materialized
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "view",
+				ValidateFunc: validation.StringInSlice([]string{"view", "late_binding", "materialized"}, false),
+			},
+			"auto_refresh": { //materialized only
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"backup": { //materialized only
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  true,
+			},
+			"diststyle": { //materialized only, EVEN, ALL or KEY
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"EVEN", "ALL", "KEY"}, true),
+			},
+			"distkey": { //materialized only
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"sortkey": { //materialized only
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"owner": { //usesysid, defaults to user specified in provider
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"database": { //Defaults to the database specified in provider
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"comment": { //Set with COMMENT ON VIEW
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"definition_hash": { //Hash of the definition in pg_views at the last apply
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceRedshiftViewExists(d *schema.ResourceData, meta interface{}) (b bool, e error) {
+	// Exists - This is called to verify a resource still exists. It is called prior to Read,
+	// and lowers the burden of Read to be able to assume the resource exists.
+	client, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return false, connErr
+	}
+
+	var name string
+
+	err := client.QueryRow("SELECT relname FROM pg_class WHERE oid = $1 AND relkind = 'v'", d.Id()).Scan(&name)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+func resourceRedshiftViewCreate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	var materialized = d.Get("type").(string) == "materialized"
+
+	if _, ok := d.GetOk("owner"); ok && materialized {
+		return NewError("The owner of a materialized view can't be set, it is always the user specified in provider")
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	var createStatement string
+	if materialized {
+		createStatement = createMaterializedViewStatement(d)
+	} else {
+		createStatement = createOrReplaceViewStatement(d)
+	}
+
+	log.Print("Create view statement: " + createStatement)
+
+	if _, err := tx.Exec(createStatement); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Could not create view %s: %s", qualifiedName(d), err)
+	}
+
+	var oid string
+
+	err := tx.QueryRow(`SELECT c.oid FROM pg_class c
+		JOIN pg_namespace nsp ON nsp.oid = c.relnamespace
+		WHERE nsp.nspname = $1 AND c.relname = $2 AND c.relkind = 'v'`, d.Get("schema").(string), d.Get("name").(string)).Scan(&oid)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Could not find view %s after creating it: %s", qualifiedName(d), err)
+	}
+
+	d.SetId(oid)
+
+	if v, ok := d.GetOk("owner"); ok {
+		if err := alterViewOwner(tx, d, v.(int)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if v, ok := d.GetOk("comment"); ok {
+		if err := setComment(tx, "VIEW", qualifiedName(d), v.(string)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := readRedshiftViewAfterApply(d, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func resourceRedshiftViewRead(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	definition, err := readRedshiftView(d, redshiftClient)
+	if err != nil {
+		return err
+	}
+
+	if hash := sha256Hex(definition); d.Get("definition_hash").(string) != "" && hash != d.Get("definition_hash").(string) {
+		log.Printf("Definition of view %s has changed outside terraform", qualifiedName(d))
+		d.Set("query", definition)
+	}
+
+	return nil
+}
+
+// Reads everything but the query, and returns the definition from pg_views
+func readRedshiftView(d *schema.ResourceData, q Queryer) (string, error) {
+
+	var (
+		schemaName string
+		name       string
+		owner      int
+		definition sql.NullString
+	)
+
+	err := q.QueryRow(`SELECT nsp.nspname, c.relname, c.relowner, pgv.definition
+		FROM pg_class c
+		JOIN pg_namespace nsp ON nsp.oid = c.relnamespace
+		LEFT JOIN pg_views pgv ON pgv.schemaname = nsp.nspname AND pgv.viewname = c.relname
+		WHERE c.oid = $1`, d.Id()).Scan(&schemaName, &name, &owner, &definition)
+	if err != nil {
+		log.Print(err)
+		return "", err
+	}
+
+	comment, err := readComment(q, d.Id(), "pg_class")
+	if err != nil {
+		log.Print(err)
+		return "", err
+	}
+
+	d.Set("schema", schemaName)
+	d.Set("name", name)
+	d.Set("owner", owner)
+	d.Set("comment", comment)
+
+	if d.Get("type").(string) == "materialized" {
+		var autoRefresh string
+		err := q.QueryRow(`SELECT autorefresh FROM stv_mv_info
+			WHERE db_name = current_database() AND schema = $1 AND name = $2`, schemaName, name).Scan(&autoRefresh)
+		if err != nil && err != sql.ErrNoRows {
+			log.Print(err)
+			return "", err
+		}
+		d.Set("auto_refresh", autoRefresh == "t" || autoRefresh == "1")
+	}
+
+	return definition.String, nil
+}
+
+// After an apply, the definition Redshift stored is the one expected from now on
+func readRedshiftViewAfterApply(d *schema.ResourceData, q Queryer) error {
+
+	definition, err := readRedshiftView(d, q)
+	if err != nil {
+		return err
+	}
+
+	d.Set("definition_hash", sha256Hex(definition))
+
+	return nil
+}
+
+func resourceRedshiftViewUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	tx, txErr := redshiftClient.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Could not begin redshift transaction: %s", txErr)
+	}
+
+	//Materialized views are recreated if the query changes
+	if d.HasChange("query") && d.Get("type").(string) != "materialized" {
+		var replaceStatement = createOrReplaceViewStatement(d)
+
+		log.Print("Replace view statement: " + replaceStatement)
+
+		if _, err := tx.Exec(replaceStatement); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Could not replace view %s: %s", qualifiedName(d), err)
+		}
+	}
+
+	if d.HasChange("auto_refresh") && d.Get("type").(string) == "materialized" {
+		if _, err := tx.Exec("ALTER MATERIALIZED VIEW " + qualifiedName(d) + " AUTO REFRESH " + yesNo(d.Get("auto_refresh").(bool))); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if d.HasChange("owner") {
+		if err := alterViewOwner(tx, d, d.Get("owner").(int)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if d.HasChange("comment") {
+		if err := setComment(tx, "VIEW", qualifiedName(d), d.Get("comment").(string)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := readRedshiftViewAfterApply(d, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func resourceRedshiftViewDelete(d *schema.ResourceData, meta interface{}) error {
+
+	redshiftClient, connErr := meta.(*Client).Connect(d.Get("database").(string))
+	if connErr != nil {
+		return connErr
+	}
+
+	var dropStatement = "DROP VIEW " + qualifiedName(d)
+	if d.Get("type").(string) == "materialized" {
+		dropStatement = "DROP MATERIALIZED VIEW " + qualifiedName(d)
+	}
+
+	log.Print("Drop view statement: " + dropStatement)
+
+	if _, err := redshiftClient.Exec(dropStatement); err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
+
+func resourceRedshiftViewCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || d.Get("type").(string) != "materialized" {
+		return nil
+	}
+
+	if d.HasChange("query") {
+		if err := d.ForceNew("query"); err != nil {
+			return err
+		}
+	}
+
+	if o, n := d.GetChange("owner"); o.(int) != n.(int) && n.(int) != 0 {
+		return NewError("The owner of a materialized view can't be changed")
+	}
+	return nil
+}
+
+func createOrReplaceViewStatement(d *schema.ResourceData) string {
+
+	var statement = "CREATE OR REPLACE VIEW " + qualifiedName(d) + " AS " + strings.TrimSpace(d.Get("query").(string))
+
+	if d.Get("type").(string) == "late_binding" {
+		statement += " WITH NO SCHEMA BINDING"
+	}
+	return statement
+}
+
+func createMaterializedViewStatement(d *schema.ResourceData) string {
+
+	var statement = "CREATE MATERIALIZED VIEW " + qualifiedName(d) + " BACKUP " + yesNo(d.Get("backup").(bool))
+
+	if v, ok := d.GetOk("diststyle"); ok {
+		statement += " DISTSTYLE " + strings.ToUpper(v.(string))
+	}
+	if v, ok := d.GetOk("distkey"); ok {
+		statement += " DISTKEY (" + v.(string) + ")"
+	}
+	if v, ok := d.GetOk("sortkey"); ok {
+		var columns []string
+		for _, column := range v.([]interface{}) {
+			columns = append(columns, column.(string))
+		}
+		statement += " SORTKEY (" + strings.Join(columns, ", ") + ")"
+	}
+
+	return statement + " AUTO REFRESH " + yesNo(d.Get("auto_refresh").(bool)) + " AS " + strings.TrimSpace(d.Get("query").(string))
+}
+
+func alterViewOwner(tx *sql.Tx, d *schema.ResourceData, owner int) error {
+
+	username, err := GetUsernameForUsesysid(tx, owner)
+	if err != nil {
+		return fmt.Errorf("Could not find redshift user %d: %s", owner, err)
+	}
+
+	if _, err := tx.Exec("ALTER TABLE " + qualifiedName(d) + " OWNER TO " + username); err != nil {
+		return err
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "YES"
+	}
+	return "NO"
+}
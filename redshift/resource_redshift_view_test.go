@@ -0,0 +1,87 @@
+package redshift
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestCreateViewStatements(t *testing.T) {
+	var view = func(raw map[string]interface{}) *schema.ResourceData {
+		raw["schema"] = "etl"
+		raw["name"] = "daily_orders"
+		raw["query"] = "\n  SELECT day, count(*) FROM etl.orders GROUP BY day\n"
+		return schema.TestResourceDataRaw(t, redshiftView().Schema, raw)
+	}
+
+	cases := []struct {
+		name      string
+		statement string
+		expected  string
+	}{
+		{
+			"view",
+			createOrReplaceViewStatement(view(map[string]interface{}{})),
+			`CREATE OR REPLACE VIEW "etl"."daily_orders" AS SELECT day, count(*) FROM etl.orders GROUP BY day`,
+		},
+		{
+			"late binding",
+			createOrReplaceViewStatement(view(map[string]interface{}{"type": "late_binding"})),
+			`CREATE OR REPLACE VIEW "etl"."daily_orders" AS SELECT day, count(*) FROM etl.orders GROUP BY day WITH NO SCHEMA BINDING`,
+		},
+		{
+			"materialized",
+			createMaterializedViewStatement(view(map[string]interface{}{
+				"type": "materialized", "backup": false, "diststyle": "key", "distkey": "day", "sortkey": []interface{}{"day"}, "auto_refresh": true,
+			})),
+			`CREATE MATERIALIZED VIEW "etl"."daily_orders" BACKUP NO DISTSTYLE KEY DISTKEY (day) SORTKEY (day) AUTO REFRESH YES AS SELECT day, count(*) FROM etl.orders GROUP BY day`,
+		},
+	}
+
+	for _, c := range cases {
+		if c.statement != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, c.statement)
+		}
+	}
+}
+
+func TestResourceRedshiftViewReadDefinitionDrift(t *testing.T) {
+	const (
+		configured = "SELECT day, count(*) FROM etl.orders GROUP BY day"
+		stored     = " SELECT orders.\"day\", count(*) AS count FROM etl.orders GROUP BY orders.\"day\";"
+	)
+
+	cases := []struct {
+		name           string
+		definitionHash string
+		expected       string
+	}{
+		{"unchanged", sha256Hex(stored), configured},
+		{"changed outside terraform", sha256Hex("SELECT 1;"), stored},
+		{"no hash yet", "", configured},
+	}
+
+	for _, c := range cases {
+		var db = newFakeDb(
+			fakeResult{match: "FROM pg_class", rows: [][]driver.Value{{"etl", "daily_orders", int64(100), stored}}},
+			fakeResult{match: "FROM pg_description", rows: [][]driver.Value{{"Orders per day"}}},
+		)
+		var d = schema.TestResourceDataRaw(t, redshiftView().Schema, map[string]interface{}{
+			"schema": "etl", "name": "daily_orders", "query": configured,
+		})
+		d.SetId("1000")
+		d.Set("definition_hash", c.definitionHash)
+
+		if err := resourceRedshiftViewRead(d, &Client{db: db}); err != nil {
+			t.Fatalf("%s: err: %s", c.name, err)
+		}
+		if query := d.Get("query").(string); query != c.expected {
+			t.Errorf("%s: expected query %q, got %q", c.name, c.expected, query)
+		}
+		if comment := d.Get("comment").(string); comment != "Orders per day" {
+			t.Errorf("%s: expected the comment to be read, got %q", c.name, comment)
+		}
+		db.Close()
+	}
+}